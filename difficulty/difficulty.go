@@ -0,0 +1,226 @@
+// Package difficulty calculates the difficulty rating of parsed .qua maps
+// locally, the same way the game does.
+//
+// It is a port of the game's keys difficulty processor. Notes are grouped
+// into chords per hand, each chord is given a strain from the finger action
+// leading to the next chord on the same hand, the strain is adjusted for
+// rolls and jacks that can be manipulated and for long notes, and the rating
+// is the mean strain of the hardest sections of the map, adjusted for how
+// continuous and how long the map is.
+package difficulty
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/maskeddd/go-quaver/qua"
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// SectionLength is the length of a section in milliseconds of real time.
+const SectionLength = 1000
+
+const (
+	// topSections is the fraction of the hardest sections the rating is the
+	// mean of.
+	topSections = 0.4
+
+	maxContinuity = 1.0
+	avgContinuity = 0.85
+	minContinuity = 0.6
+
+	maxContinuityAdjustment = 1.05
+	avgContinuityAdjustment = 1.0
+	minContinuityAdjustment = 0.9
+)
+
+// Options are the modifiers that change a map's difficulty.
+type Options struct {
+	// Rate is the audio rate. Zero means 1.0x.
+	Rate float64
+	// Mirror flips the lanes of the map, leaving the scratch lane in place.
+	Mirror bool
+	// NoLongNotes turns long notes into normal notes.
+	NoLongNotes bool
+}
+
+// Rating is the difficulty of a map.
+type Rating struct {
+	// Overall is the difficulty rating of the map.
+	Overall float64
+	// Rate is the rate the map was rated at.
+	Rate float64
+	// Sections holds the strain of each SectionLength of the map.
+	Sections []Section
+}
+
+// Section is the strain of part of a map.
+type Section struct {
+	// Start is the map time the section starts at. The first section starts
+	// at the first note.
+	Start int
+	// Strain is the mean strain of the chords in the section, or zero if it
+	// has none.
+	Strain float64
+	// Notes is the number of notes in the section.
+	Notes int
+}
+
+// Calculate calculates the difficulty of m with the given options. Maps with
+// fewer than two notes are rated zero. Notes in the scratch lane are not
+// rated.
+func Calculate(m *qua.Map, opts Options) *Rating {
+	rate := opts.Rate
+	if rate <= 0 {
+		rate = 1
+	}
+
+	r := &Rating{Rate: rate}
+	if len(m.HitObjects) < 2 {
+		return r
+	}
+
+	notes := prepare(m, opts, rate)
+	keys := m.KeyCount()
+	if m.HasScratchKey {
+		keys--
+	}
+	density := noteDensity(m, rate)
+
+	// Lane 4 in 7K may be played by either thumb, so 7K maps are rated with
+	// it in each hand and the two ratings averaged.
+	if m.Mode != quaver.GameMode7K {
+		chords := solve(notes, lanes4K[:], keys, handRight, density)
+		r.Overall, r.Sections = overall(chords, rate)
+		return r
+	}
+
+	left, sections := overall(solve(notes, lanes7K[:], keys, handLeft, density), rate)
+	right, other := overall(solve(notes, lanes7K[:], keys, handRight, density), rate)
+	r.Overall = (left + right) / 2
+	for i := range sections {
+		sections[i].Strain = (sections[i].Strain + other[i].Strain) / 2
+	}
+	r.Sections = sections
+	return r
+}
+
+// noteDensity returns the mean number of notes per second of m played at
+// rate, scaled the way the game scales it for rates.
+func noteDensity(m *qua.Map, rate float64) float64 {
+	return 1000 * float64(len(m.HitObjects)) / (float64(m.Length()) * (-0.5*rate + 1.5))
+}
+
+// overall returns the rating of chords, whose strains must be set, and the
+// sections it was calculated from.
+func overall(chords []*chord, rate float64) (float64, []Section) {
+	if len(chords) == 0 {
+		return 0, nil
+	}
+
+	start, end := math.Inf(1), math.Inf(-1)
+	for _, c := range chords {
+		start = min(start, c.start)
+		end = max(end, c.start, c.end)
+	}
+
+	var sections []Section
+	var strains []float64
+	for t := start; t < end; t += SectionLength {
+		s := Section{Start: int(math.Round(t * rate))}
+		var chordCount int
+		for _, c := range chords {
+			if c.start >= t && c.start < t+SectionLength {
+				s.Strain += c.strain
+				s.Notes += len(c.notes)
+				chordCount++
+			}
+		}
+		if chordCount > 0 {
+			s.Strain /= float64(chordCount)
+		}
+		sections = append(sections, s)
+		strains = append(strains, s.Strain)
+	}
+
+	slices.SortFunc(strains, func(a, b float64) int {
+		return cmp.Compare(b, a)
+	})
+	if len(strains) == 0 || strains[0] == 0 {
+		return 0, sections
+	}
+
+	// Maps shorter than three sections would have no hardest 40%, so at least
+	// the hardest section is used.
+	top := strains[:max(1, int(float64(len(strains))*topSections))]
+	var hardest float64
+	for _, s := range top {
+		hardest += s
+	}
+	hardest /= float64(len(top))
+
+	// Continuity is how close the sections with notes are to the hardest.
+	var continuity float64
+	var filled int
+	for _, s := range strains {
+		if s > 0 {
+			continuity += math.Sqrt(s / hardest)
+			filled++
+		}
+	}
+	continuity /= float64(filled)
+
+	var adjustment float64
+	if continuity > avgContinuity {
+		f := 1 - (continuity-avgContinuity)/(maxContinuity-avgContinuity)
+		adjustment = min(avgContinuityAdjustment, max(minContinuityAdjustment,
+			f*avgContinuityAdjustment+(1-f)*minContinuityAdjustment))
+	} else {
+		f := 1 - (continuity-minContinuity)/(avgContinuity-minContinuity)
+		adjustment = min(maxContinuityAdjustment, max(avgContinuityAdjustment,
+			f*maxContinuityAdjustment+(1-f)*avgContinuityAdjustment))
+	}
+
+	// Short maps are scaled down by their drain time.
+	drain := float64(len(strains)) * continuity * SectionLength
+	short := min(1, max(0.75, 0.25*math.Sqrt(drain/1000)+0.75))
+
+	return hardest * adjustment * short, sections
+}
+
+// note is a hit object in real time.
+type note struct {
+	start, end float64
+	lane       int
+	long       bool
+}
+
+// prepare converts the hit objects of m to notes in real time, sorted by
+// start time.
+func prepare(m *qua.Map, opts Options, rate float64) []*note {
+	keys := m.KeyCount()
+	if m.HasScratchKey {
+		keys--
+	}
+
+	notes := make([]*note, 0, len(m.HitObjects))
+	for _, h := range m.HitObjects {
+		n := &note{
+			start: float64(h.StartTime) / rate,
+			lane:  h.Lane,
+			long:  h.IsLongNote() && !opts.NoLongNotes,
+		}
+		if n.long {
+			n.end = float64(h.EndTime) / rate
+		}
+		if opts.Mirror && n.lane <= keys {
+			n.lane = keys + 1 - n.lane
+		}
+		notes = append(notes, n)
+	}
+	slices.SortStableFunc(notes, func(a, b *note) int {
+		return cmp.Compare(a.start, b.start)
+	})
+	return notes
+}
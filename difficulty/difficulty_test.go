@@ -0,0 +1,234 @@
+package difficulty
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/qua"
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestCalculateNegativeStartTimes(t *testing.T) {
+	m := &qua.Map{Mode: quaver.GameMode4K}
+	for i, start := range []int{-1500, -1200, -400, 0, 250, 1100} {
+		m.HitObjects = append(m.HitObjects, qua.HitObject{StartTime: start, Lane: i%4 + 1})
+	}
+
+	// The notes span 2600ms of map time, or 1733ms of real time at 1.5x.
+	for rate, sections := range map[float64]int{1: 3, 1.5: 2} {
+		r := Calculate(m, Options{Rate: rate})
+		if len(r.Sections) != sections {
+			t.Fatalf("%vx: %d sections, want %d", rate, len(r.Sections), sections)
+		}
+		if want := -1500; r.Sections[0].Start != want {
+			t.Errorf("%vx: first section starts at %d, want %d", rate, r.Sections[0].Start, want)
+		}
+
+		var notes int
+		for _, s := range r.Sections {
+			notes += s.Notes
+		}
+		if notes != len(m.HitObjects) {
+			t.Errorf("%vx: sections hold %d notes, want %d", rate, notes, len(m.HitObjects))
+		}
+	}
+}
+
+func TestMirrorKeepsScratchLane(t *testing.T) {
+	m := &qua.Map{
+		Mode:          quaver.GameMode7K,
+		HasScratchKey: true,
+		HitObjects: []qua.HitObject{
+			{StartTime: 0, Lane: 1},
+			{StartTime: 100, Lane: 8},
+			{StartTime: 200, Lane: 3},
+		},
+	}
+
+	notes := prepare(m, Options{Mirror: true}, 1)
+	for i, want := range []int{7, 8, 5} {
+		if notes[i].lane != want {
+			t.Errorf("note %d in lane %d, want %d", i, notes[i].lane, want)
+		}
+	}
+}
+
+func TestCoefficient(t *testing.T) {
+	jack := curves[actionSimpleJack]
+	tests := []struct {
+		duration, density float64
+		want              float64
+	}{
+		{duration: 20, density: 10, want: 68},
+		{duration: 40, density: 10, want: 68},
+		{duration: 180, density: 10, want: 1 + 67*math.Pow(0.5, 1.17)},
+		{duration: 320, density: 10, want: 1},
+		{duration: 500, density: 10, want: 1},
+		// Past the upper bound, sparse maps are rated by their density.
+		{duration: 500, density: 0.5, want: 0.4},
+		{duration: 500, density: 2, want: 2*0.266 + 0.134},
+		{duration: 320, density: 2, want: 1},
+	}
+	for _, tt := range tests {
+		if got := jack.coefficient(tt.duration, tt.density); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("coefficient(%v, %v) = %v, want %v", tt.duration, tt.density, got, tt.want)
+		}
+	}
+}
+
+func TestSolveActions(t *testing.T) {
+	// Each pattern is played by the left hand in 4K, where lane 1 is the
+	// middle finger and lane 2 the index finger.
+	tests := []struct {
+		name  string
+		lanes [][]int
+		want  action
+	}{
+		{"roll", [][]int{{1}, {2}}, actionRoll},
+		{"jack", [][]int{{1}, {1}}, actionSimpleJack},
+		{"chord jack", [][]int{{1, 2}, {1, 2}}, actionSimpleJack},
+		{"into chord", [][]int{{1}, {1, 2}}, actionTechnicalJack},
+		{"index into chord", [][]int{{2}, {1, 2}}, actionTechnicalJack},
+		// The game checks the chord's finger set as a bit index, so leaving a
+		// chord by one of its fingers is a bracket rather than a jack.
+		{"out of chord", [][]int{{1, 2}, {2}}, actionBracket},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notes []*note
+			for i, chord := range tt.lanes {
+				for _, l := range chord {
+					notes = append(notes, &note{start: float64(i * 100), lane: l})
+				}
+			}
+
+			chords := solve(notes, lanes4K[:], 4, handRight, 10)
+			if len(chords) != 2 {
+				t.Fatalf("%d chords, want 2", len(chords))
+			}
+			if chords[0].action != tt.want || chords[0].next != chords[1] || chords[0].duration != 100 {
+				t.Errorf("action %v to %p after %vms, want %v to %p after 100ms",
+					chords[0].action, chords[0].next, chords[0].duration, tt.want, chords[1])
+			}
+		})
+	}
+}
+
+func TestGroupChords(t *testing.T) {
+	notes := []*note{
+		{start: 0, lane: 1},
+		{start: 8, lane: 2},
+		{start: 8, lane: 3},
+		{start: 100, lane: 1},
+		{start: 109, lane: 2},
+		{start: 200, lane: 1},
+		{start: 204, lane: 1},
+	}
+	chords := solve(notes, lanes4K[:], 4, handRight, 10)
+
+	// Notes within 8ms on the same hand are one chord, and a second note for
+	// a finger already in the chord is dropped.
+	want := []int{2, 1, 1, 1, 1}
+	if len(chords) != len(want) {
+		t.Fatalf("%d chords, want %d", len(chords), len(want))
+	}
+	for i, c := range chords {
+		if len(c.notes) != want[i] {
+			t.Errorf("chord %d at %v has %d notes, want %d", i, c.start, len(c.notes), want[i])
+		}
+	}
+}
+
+func TestCalculateRate(t *testing.T) {
+	m := &qua.Map{Mode: quaver.GameMode4K}
+	for i := range 200 {
+		m.HitObjects = append(m.HitObjects, qua.HitObject{StartTime: i * 150, Lane: i%4 + 1})
+	}
+
+	slow := Calculate(m, Options{Rate: 0.5})
+	normal := Calculate(m, Options{})
+	fast := Calculate(m, Options{Rate: 2})
+	if !(slow.Overall < normal.Overall && normal.Overall < fast.Overall) {
+		t.Errorf("ratings at 0.5x, 1x and 2x = %v, %v, %v, want increasing", slow.Overall, normal.Overall, fast.Overall)
+	}
+	if normal.Rate != 1 || fast.Rate != 2 {
+		t.Errorf("rates = %v, %v, want 1, 2", normal.Rate, fast.Rate)
+	}
+	if len(fast.Sections) != 15 || fast.Sections[1].Start != 2000 {
+		t.Errorf("2x has %d sections with the second at %d, want 15 with the second at 2000",
+			len(fast.Sections), fast.Sections[1].Start)
+	}
+}
+
+func TestCalculateLongNotes(t *testing.T) {
+	m := &qua.Map{Mode: quaver.GameMode4K}
+	for i := range 100 {
+		m.HitObjects = append(m.HitObjects, qua.HitObject{StartTime: i * 100, EndTime: i*100 + 80, Lane: i%4 + 1})
+	}
+
+	ln := Calculate(m, Options{})
+	nln := Calculate(m, Options{NoLongNotes: true})
+	if ln.Overall <= nln.Overall {
+		t.Errorf("rating with long notes = %v, want more than %v without", ln.Overall, nln.Overall)
+	}
+}
+
+func TestCalculate7KMirror(t *testing.T) {
+	m := &qua.Map{Mode: quaver.GameMode7K}
+	for i := range 300 {
+		m.HitObjects = append(m.HitObjects, qua.HitObject{StartTime: i * 90, Lane: i*3%7 + 1})
+	}
+
+	// 7K lanes are played symmetrically and lane 4 by either hand, so
+	// mirroring does not change the rating.
+	r := Calculate(m, Options{})
+	mirrored := Calculate(m, Options{Mirror: true})
+	if r.Overall <= 0 || math.Abs(r.Overall-mirrored.Overall) > 1e-9 {
+		t.Errorf("rating = %v, mirrored %v, want equal and positive", r.Overall, mirrored.Overall)
+	}
+}
+
+func TestCalculateTooFewNotes(t *testing.T) {
+	m := &qua.Map{Mode: quaver.GameMode4K, HitObjects: []qua.HitObject{{StartTime: 100, Lane: 1}}}
+	if r := Calculate(m, Options{}); r.Overall != 0 || r.Sections != nil {
+		t.Errorf("Calculate = %+v, want a zero rating", r)
+	}
+}
+
+// rankedTolerance is how far Calculate may be from the rating the game gives
+// a ranked map.
+const rankedTolerance = 0.05
+
+// TestCalculateRanked compares Calculate to the DifficultyRating of ranked
+// maps. testdata/ranked holds .qua files downloaded from the game alongside
+// ratings.json, which maps each file name to its DifficultyRating at 1.0x.
+func TestCalculateRanked(t *testing.T) {
+	b, err := os.ReadFile("testdata/ranked/ratings.json")
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skip("no ranked maps in testdata/ranked")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ratings map[string]float64
+	if err := json.Unmarshal(b, &ratings); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range ratings {
+		t.Run(name, func(t *testing.T) {
+			m, err := qua.ParseFile(filepath.Join("testdata/ranked", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Calculate(m, Options{}).Overall; math.Abs(got-want) > rankedTolerance {
+				t.Errorf("Overall = %.4f, want %.4f ± %v", got, want, rankedTolerance)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package difficulty
+
+import (
+	"github.com/maskeddd/go-quaver/qua"
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// ModifierOptions returns the options matching the rate, Mirror and No Long
+// Notes modifiers in mods.
+func ModifierOptions(mods quaver.Modifier) Options {
+	return Options{
+		Rate:        mods.Rate(),
+		Mirror:      mods&quaver.ModifierMirror != 0,
+		NoLongNotes: mods&quaver.ModifierNoLongNotes != 0,
+	}
+}
+
+// PerformanceRating estimates the performance rating of a play on m with the
+// given accuracy and modifiers, rating m with Calculate at the rate the play
+// was made at.
+func PerformanceRating(m *qua.Map, accuracy float64, mods quaver.Modifier) float64 {
+	r := Calculate(m, ModifierOptions(mods))
+	return quaver.PerformanceRating(r.Overall, accuracy)
+}
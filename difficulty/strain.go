@@ -0,0 +1,305 @@
+package difficulty
+
+import "math"
+
+// finger is a set of fingers.
+type finger int
+
+const (
+	fingerIndex finger = 1 << iota
+	fingerMiddle
+	fingerRing
+	fingerPinkie
+	fingerThumb
+)
+
+type hand int
+
+const (
+	handLeft hand = iota
+	handRight
+	// handAmbiguous is a lane either hand may play.
+	handAmbiguous
+)
+
+// lane is the finger and hand that play a lane.
+type lane struct {
+	finger finger
+	hand   hand
+}
+
+var lanes4K = [...]lane{
+	{fingerMiddle, handLeft},
+	{fingerIndex, handLeft},
+	{fingerIndex, handRight},
+	{fingerMiddle, handRight},
+}
+
+var lanes7K = [...]lane{
+	{fingerRing, handLeft},
+	{fingerMiddle, handLeft},
+	{fingerIndex, handLeft},
+	{fingerThumb, handAmbiguous},
+	{fingerIndex, handRight},
+	{fingerMiddle, handRight},
+	{fingerRing, handRight},
+}
+
+// action is the way a hand moves from one chord to the next.
+type action int
+
+const (
+	actionNone action = iota
+	// actionRoll moves between single notes played by different fingers.
+	actionRoll
+	// actionSimpleJack repeats the same fingers.
+	actionSimpleJack
+	// actionTechnicalJack repeats some of the fingers of a chord.
+	actionTechnicalJack
+	// actionBracket moves between chords played by different fingers.
+	actionBracket
+)
+
+// curve gives the strain coefficient of an action from its duration. The
+// coefficient is max at lower milliseconds or less and falls to 1 at upper
+// milliseconds along a curve with exponent exp.
+type curve struct {
+	lower, upper float64
+	max          float64
+	exp          float64
+}
+
+var curves = [...]curve{
+	actionRoll:          {lower: 30, upper: 230, max: 55, exp: 1.13},
+	actionSimpleJack:    {lower: 40, upper: 320, max: 68, exp: 1.17},
+	actionTechnicalJack: {lower: 40, upper: 330, max: 70, exp: 1.14},
+	actionBracket:       {lower: 30, upper: 230, max: 56, exp: 1.13},
+}
+
+// coefficient returns the strain coefficient of an action taking duration
+// milliseconds in a map with the given note density. Slow actions in sparse
+// maps are rated by the density alone.
+func (c curve) coefficient(duration, density float64) float64 {
+	ratio := max(0, (duration-c.lower)/(c.upper-c.lower))
+	if ratio > 1 && density < 4 {
+		if density < 1 {
+			return 0.4
+		}
+		return density*0.266 + 0.134
+	}
+	return 1 + (c.max-1)*math.Pow(1-min(1, ratio), c.exp)
+}
+
+const (
+	// chordTolerance is the largest difference in milliseconds between notes
+	// played as one chord.
+	chordTolerance = 8
+
+	// Long notes shorter than lnLayerThreshold are raised by lnBaseMultiplier,
+	// fading out over the next lnLayerTolerance milliseconds. Playing the
+	// next chord on the hand while holding one raises it again.
+	lnBaseMultiplier = 0.6
+	lnLayerTolerance = 60
+	lnLayerThreshold = 93.7
+	lnReleaseAfter   = 1.0
+	lnReleaseBefore  = 1.3
+	lnTapMultiplier  = 1.05
+
+	// Jacks faster than vibroDuration plus vibroTolerance are scaled by as
+	// little as vibroMultiplier, and by as little as vibroLength again after
+	// vibroMaxLength jacks in a row.
+	vibroDuration   = 88.2
+	vibroTolerance  = 88.2
+	vibroMultiplier = 0.75
+	vibroLength     = 0.3
+	vibroMaxLength  = 6
+
+	// Rolls whose durations differ by rollRatioTolerance times or more are
+	// lowered by rollRatioScale per unit of the ratio, and scaled by as
+	// little as rollLength again after rollMaxLength such rolls.
+	rollRatioTolerance = 2
+	rollRatioScale     = 0.25
+	rollLength         = 0.6
+	rollMaxLength      = 14
+)
+
+// chord is the notes a hand plays together.
+type chord struct {
+	start, end float64
+	notes      []*note
+	fingers    finger
+	hand       hand
+
+	// next is the next chord played by the same hand.
+	next     *chord
+	action   action
+	duration float64
+
+	coefficient    float64
+	rollMultiplier float64
+	jackMultiplier float64
+	lnMultiplier   float64
+	strain         float64
+}
+
+// solve groups notes into chords and sets the strain of each. lanes maps
+// lanes to fingers and hands, with ambiguous lanes played by ambiguous.
+// Notes past keys are in the scratch lane and are skipped.
+func solve(notes []*note, lanes []lane, keys int, ambiguous hand, density float64) []*chord {
+	var chords []*chord
+	for _, n := range notes {
+		if n.lane < 1 || n.lane > keys {
+			continue
+		}
+		l := lanes[n.lane-1]
+		if l.hand == handAmbiguous {
+			l.hand = ambiguous
+		}
+		chords = append(chords, &chord{
+			start:          n.start,
+			end:            n.end,
+			notes:          []*note{n},
+			fingers:        l.finger,
+			hand:           l.hand,
+			coefficient:    1,
+			rollMultiplier: 1,
+			jackMultiplier: 1,
+			lnMultiplier:   1,
+		})
+	}
+
+	chords = groupChords(chords)
+	solveActions(chords, density)
+	solveRolls(chords)
+	solveJacks(chords)
+	solveLongNotes(chords)
+
+	for _, c := range chords {
+		c.strain = c.coefficient * c.rollMultiplier * c.jackMultiplier * c.lnMultiplier
+	}
+	return chords
+}
+
+// groupChords merges notes played by the same hand within chordTolerance of
+// each other. A note played by a finger already in the chord is dropped.
+func groupChords(chords []*chord) []*chord {
+	for i := 0; i < len(chords)-1; i++ {
+		for j := i + 1; j < len(chords); j++ {
+			if chords[j].start-chords[i].start > chordTolerance {
+				break
+			}
+			if chords[i].hand != chords[j].hand {
+				continue
+			}
+			if chords[i].fingers&chords[j].fingers == 0 {
+				chords[i].notes = append(chords[i].notes, chords[j].notes...)
+				chords[i].fingers |= chords[j].fingers
+			}
+			chords = append(chords[:j], chords[j+1:]...)
+			j--
+		}
+	}
+	return chords
+}
+
+// solveActions links each chord to the next chord on the same hand and sets
+// its action and strain coefficient.
+func solveActions(chords []*chord, density float64) {
+	for i, c := range chords {
+		for _, next := range chords[i+1:] {
+			if next.hand != c.hand || next.start <= c.start {
+				continue
+			}
+
+			c.next = next
+			c.duration = next.start - c.start
+			switch {
+			case len(c.notes) == 1 && len(next.notes) == 1 && c.fingers != next.fingers:
+				c.action = actionRoll
+			case c.fingers == next.fingers:
+				c.action = actionSimpleJack
+			case next.fingers&(1<<(c.fingers-1)) != 0:
+				// The game tests the next chord's fingers against this bit
+				// of the current chord's finger set.
+				c.action = actionTechnicalJack
+			default:
+				c.action = actionBracket
+			}
+			c.coefficient = curves[c.action].coefficient(c.duration, density)
+			break
+		}
+	}
+}
+
+// solveRolls lowers the strain of rolls that can be played as jacks because
+// one of their two durations is much shorter than the other.
+func solveRolls(chords []*chord) {
+	length := 0
+	for _, c := range chords {
+		found := false
+		if mid := c.next; mid != nil && mid.next != nil &&
+			c.action == actionRoll && mid.action == actionRoll && c.fingers == mid.next.fingers {
+			ratio := max(c.duration/mid.duration, mid.duration/c.duration)
+			if ratio >= rollRatioTolerance {
+				m := 1 / (1 + (ratio-1)*rollRatioScale)
+				l := 1 - float64(length)/rollMaxLength*(1-rollLength)
+				c.rollMultiplier = m * l
+				found = true
+				if length < rollMaxLength {
+					length++
+				}
+			}
+		}
+		if !found && length > 0 {
+			length--
+		}
+	}
+}
+
+// solveJacks lowers the strain of fast repeated jacks, which can be played
+// by vibrating the fingers.
+func solveJacks(chords []*chord) {
+	length := 0
+	for _, c := range chords {
+		found := false
+		if c.next != nil && c.action == actionSimpleJack && c.next.action == actionSimpleJack {
+			d := min(1, max(0, (vibroDuration+vibroTolerance-c.duration)/vibroTolerance))
+			m := 1 - d*(1-vibroMultiplier)
+			l := 1 - float64(length)/vibroMaxLength*(1-vibroLength)
+			c.jackMultiplier = m * l
+			found = true
+			if length < vibroMaxLength {
+				length++
+			}
+		}
+		if !found {
+			length = 0
+		}
+	}
+}
+
+// solveLongNotes raises the strain of long notes, which is raised further
+// when the same hand plays the next chord while still holding them.
+func solveLongNotes(chords []*chord) {
+	for _, c := range chords {
+		if !c.notes[0].long {
+			continue
+		}
+
+		d := 1 - min(1, max(0, (lnLayerThreshold+lnLayerTolerance-(c.end-c.start))/lnLayerTolerance))
+		c.lnMultiplier = 1 + (1-d)*lnBaseMultiplier
+
+		next := c.next
+		if next == nil || next.start >= c.end-lnLayerThreshold || next.start < c.start+lnLayerThreshold {
+			continue
+		}
+		switch {
+		case next.end > c.end+lnLayerThreshold:
+			c.lnMultiplier *= lnReleaseAfter
+		case next.notes[0].long:
+			c.lnMultiplier *= lnReleaseBefore
+		default:
+			c.lnMultiplier *= lnTapMultiplier
+		}
+	}
+}
@@ -2,4 +2,10 @@ module github.com/maskeddd/go-quaver
 
 require github.com/google/go-querystring v1.1.0
 
+require golang.org/x/time v0.9.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+require github.com/ulikunitz/xz v0.5.12
+
 go 1.22
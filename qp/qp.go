@@ -0,0 +1,211 @@
+// Package qp reads and writes Quaver .qp mapset packages.
+//
+// A .qp file is a zip archive holding the .qua files of a mapset along with
+// the audio, backgrounds and other assets they refer to.
+package qp
+
+import (
+	"archive/zip"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/maskeddd/go-quaver/qua"
+)
+
+// ErrChecksum is returned by Verify when a package does not match its MD5.
+var ErrChecksum = errors.New("qp: checksum mismatch")
+
+// Reader reads the files of a .qp package.
+type Reader struct {
+	// Files holds every file in the package, in archive order.
+	Files []*File
+
+	r    io.ReaderAt
+	size int64
+	zip  *zip.Reader
+}
+
+// ReadCloser is a Reader that must be closed when no longer needed.
+type ReadCloser struct {
+	Reader
+	f *os.File
+}
+
+// File is a file in a package.
+type File struct {
+	// Name is the slash separated path of the file in the package.
+	Name string
+	// Size is the uncompressed size of the file.
+	Size int64
+
+	zf *zip.File
+}
+
+// Open opens the .qp file at path.
+func Open(path string) (*ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	rc := &ReadCloser{f: f}
+	err = rc.init(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return rc, nil
+}
+
+// Close closes the package file.
+func (rc *ReadCloser) Close() error {
+	return rc.f.Close()
+}
+
+// NewReader returns a Reader reading a .qp package of the given size from r.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	qr := &Reader{}
+	err := qr.init(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return qr, nil
+}
+
+func (r *Reader) init(ra io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return fmt.Errorf("qp: %w", err)
+	}
+
+	r.r, r.size, r.zip = ra, size, zr
+	for _, zf := range zr.File {
+		if strings.HasSuffix(zf.Name, "/") {
+			continue
+		}
+		r.Files = append(r.Files, &File{
+			Name: zf.Name,
+			Size: int64(zf.UncompressedSize64),
+			zf:   zf,
+		})
+	}
+	return nil
+}
+
+// Open opens the named file, implementing fs.FS.
+func (r *Reader) Open(name string) (fs.File, error) {
+	return r.zip.Open(name)
+}
+
+// File returns the file with the given name, or nil if there is none. Names
+// are matched case-insensitively, as the game does.
+func (r *Reader) File(name string) *File {
+	for _, f := range r.Files {
+		if strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// MapFiles returns the .qua files in the package.
+func (r *Reader) MapFiles() []*File {
+	var files []*File
+	for _, f := range r.Files {
+		if f.IsMap() {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// Assets returns the files in the package that are not .qua files.
+func (r *Reader) Assets() []*File {
+	var files []*File
+	for _, f := range r.Files {
+		if !f.IsMap() {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// Maps parses every .qua file in the package, in archive order.
+func (r *Reader) Maps() ([]*qua.Map, error) {
+	var maps []*qua.Map
+	for _, f := range r.MapFiles() {
+		m, err := f.Map()
+		if err != nil {
+			return nil, err
+		}
+		maps = append(maps, m)
+	}
+	return maps, nil
+}
+
+// MD5 returns the hex encoded MD5 hash of the package, as found in
+// quaver.MapsetCompact.PackageMD5.
+func (r *Reader) MD5() (string, error) {
+	h := md5.New()
+	_, err := io.Copy(h, io.NewSectionReader(r.r, 0, r.size))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Verify checks the package against packageMD5, returning ErrChecksum if
+// they differ.
+func (r *Reader) Verify(packageMD5 string) error {
+	sum, err := r.MD5()
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sum, packageMD5) {
+		return fmt.Errorf("%w: got %v, want %v", ErrChecksum, sum, packageMD5)
+	}
+	return nil
+}
+
+// IsMap reports whether the file is a .qua file.
+func (f *File) IsMap() bool {
+	return strings.EqualFold(path.Ext(f.Name), ".qua")
+}
+
+// Open returns a ReadCloser reading the contents of the file.
+func (f *File) Open() (io.ReadCloser, error) {
+	return f.zf.Open()
+}
+
+// Map parses the file as a .qua file.
+func (f *File) Map() (*qua.Map, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := qua.Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("qp: %v: %w", f.Name, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,212 @@
+package qp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/maskeddd/go-quaver/qua"
+)
+
+// mapsetMD5 is the MD5 of testdata/mapset.qp, which holds the .qua files from
+// ../qua/testdata along with placeholder audio and background files.
+const mapsetMD5 = "ae9fe94d19b5f618fb4d8b6b722913a9"
+
+func TestWriteRead(t *testing.T) {
+	m4k, err := qua.ParseFile("../qua/testdata/4k.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m7k, err := qua.ParseFile("../qua/testdata/7k_scratch.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.AddMap("4k", m4k); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddFile("audio.mp3", strings.NewReader("audio")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddMap("7k_scratch.QUA", m7k); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := w.Create("backgrounds/bg.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(fw, "background")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"4k.qua", "audio.mp3", "7k_scratch.QUA", "backgrounds/bg.jpg"}
+	if got := names(r.Files); !reflect.DeepEqual(got, want) {
+		t.Errorf("Files = %v, want %v", got, want)
+	}
+	if got := names(r.MapFiles()); !reflect.DeepEqual(got, []string{"4k.qua", "7k_scratch.QUA"}) {
+		t.Errorf("MapFiles = %v, want [4k.qua 7k_scratch.QUA]", got)
+	}
+	if got := names(r.Assets()); !reflect.DeepEqual(got, []string{"audio.mp3", "backgrounds/bg.jpg"}) {
+		t.Errorf("Assets = %v, want [audio.mp3 backgrounds/bg.jpg]", got)
+	}
+
+	maps, err := r.Maps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(maps) != 2 || !reflect.DeepEqual(maps[0], m4k) || !reflect.DeepEqual(maps[1], m7k) {
+		t.Errorf("Maps = %+v, want the 4K and 7K maps that were added", maps)
+	}
+
+	f := r.File("Backgrounds/BG.jpg")
+	if f == nil {
+		t.Fatal("File(Backgrounds/BG.jpg) = nil, want backgrounds/bg.jpg")
+	}
+	if got := readFile(t, f); got != "background" || f.Size != int64(len(got)) {
+		t.Errorf("backgrounds/bg.jpg = %q with size %d, want %q", got, f.Size, "background")
+	}
+	if r.File("missing.png") != nil {
+		t.Error("File(missing.png) != nil")
+	}
+
+	err = fstest.TestFS(r, "4k.qua", "audio.mp3", "7k_scratch.QUA", "backgrounds/bg.jpg")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	w := NewWriter(io.Discard)
+	if _, err := w.Create("map.qua"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"MAP.qua", "", ".", "/abs.qua", "../up.qua", "a/./b.qua"} {
+		if _, err := w.Create(name); err == nil {
+			t.Errorf("Create(%q) succeeded, want error", name)
+		}
+	}
+	if err := w.AddMap("map", &qua.Map{}); err == nil {
+		t.Error("AddMap(map) succeeded alongside map.qua, want duplicate error")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	b, err := os.ReadFile("testdata/mapset.qp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := r.MD5()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != mapsetMD5 {
+		t.Errorf("MD5 = %v, want %v", sum, mapsetMD5)
+	}
+	if err := r.Verify(mapsetMD5); err != nil {
+		t.Errorf("Verify(%v) = %v", mapsetMD5, err)
+	}
+	if err := r.Verify(strings.ToUpper(mapsetMD5)); err != nil {
+		t.Errorf("Verify of the upper case hash = %v", err)
+	}
+	if err := r.Verify("d41d8cd98f00b204e9800998ecf8427e"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Verify of another hash = %v, want ErrChecksum", err)
+	}
+
+	// A single changed byte in the first file's contents no longer matches.
+	b[100] ^= 0xff
+	r, err = NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Verify(mapsetMD5); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Verify of a corrupted package = %v, want ErrChecksum", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	rc, err := Open("testdata/mapset.qp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	if err := rc.Verify(mapsetMD5); err != nil {
+		t.Error(err)
+	}
+	maps, err := rc.Maps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"4k.qua", "7k_scratch.qua"} {
+		want, err := qua.ParseFile("../qua/testdata/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i >= len(maps) || maps[i].MD5 != want.MD5 {
+			t.Errorf("map %d is not %v", i, name)
+		}
+	}
+	if got := names(rc.Assets()); !reflect.DeepEqual(got, []string{"audio.mp3", "bg.jpg"}) {
+		t.Errorf("Assets = %v, want [audio.mp3 bg.jpg]", got)
+	}
+}
+
+func TestMapParseError(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.AddFile("broken.qua", strings.NewReader("Mode: Keys9\n")); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.Maps()
+	var qerr *qua.Error
+	if !errors.As(err, &qerr) || !strings.Contains(err.Error(), "broken.qua") {
+		t.Errorf("Maps = %v, want a *qua.Error naming broken.qua", err)
+	}
+}
+
+func names(files []*File) []string {
+	var s []string
+	for _, f := range files {
+		s = append(s, f.Name)
+	}
+	return s
+}
+
+func readFile(t *testing.T, f *File) string {
+	t.Helper()
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
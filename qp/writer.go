@@ -0,0 +1,78 @@
+package qp
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/maskeddd/go-quaver/qua"
+)
+
+// Writer writes a .qp package.
+type Writer struct {
+	zw    *zip.Writer
+	names map[string]bool
+}
+
+// NewWriter returns a Writer writing a .qp package to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		zw:    zip.NewWriter(w),
+		names: make(map[string]bool),
+	}
+}
+
+// Create adds a file with the given name to the package and returns a Writer
+// for its contents, which must be written before the next call to Create,
+// AddFile, AddMap or Close.
+func (w *Writer) Create(name string) (io.Writer, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, fmt.Errorf("qp: invalid file name %q", name)
+	}
+	key := strings.ToLower(name)
+	if w.names[key] {
+		return nil, fmt.Errorf("qp: duplicate file %q", name)
+	}
+	w.names[key] = true
+
+	return w.zw.Create(name)
+}
+
+// AddFile adds a file with the given name and the contents of r.
+func (w *Writer) AddFile(name string, r io.Reader) error {
+	fw, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+// AddMap adds m encoded as a .qua file. The .qua extension is added to name
+// if it is missing.
+func (w *Writer) AddMap(name string, m *qua.Map) error {
+	if !strings.EqualFold(path.Ext(name), ".qua") {
+		name += ".qua"
+	}
+
+	b, err := qua.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("qp: %v: %w", name, err)
+	}
+
+	fw, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(b)
+	return err
+}
+
+// Close finishes writing the package. It does not close the underlying
+// writer.
+func (w *Writer) Close() error {
+	return w.zw.Close()
+}
@@ -0,0 +1,415 @@
+package qua
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// Marshal returns the .qua encoding of m.
+//
+// Fields are written in the order the game writes them, numbers use the
+// shortest representation that reads back to the same value, and fields
+// holding their default value are omitted the way the game omits them.
+// Fields Parse did not know are written back as they were read, after the
+// field they followed. Parsing the result yields a map equal to m apart from
+// MD5.
+//
+// Marshaling a map parsed from a file in this format, which is how the game
+// writes maps, reproduces the file byte for byte, so its MD5 is unchanged.
+// Files written by other tools, or by hand, are normalized instead.
+func Marshal(m *Map) ([]byte, error) {
+	var buf bytes.Buffer
+	err := Encode(&buf, m)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode writes the .qua encoding of m to w. See Marshal for the format.
+func Encode(w io.Writer, m *Map) error {
+	err := m.Validate()
+	if err != nil {
+		return err
+	}
+
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.encode(m)
+	return e.w.Flush()
+}
+
+// Hash returns the MD5 hash m would have once written by Marshal.
+func Hash(m *Map) (string, error) {
+	b, err := Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	sum := md5.Sum(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type encoder struct {
+	w *bufio.Writer
+}
+
+func (e *encoder) line(indent, key, value string) {
+	e.w.WriteString(indent)
+	e.w.WriteString(key)
+	e.w.WriteString(":")
+	if value != "" {
+		e.w.WriteString(" ")
+		e.w.WriteString(value)
+	}
+	e.w.WriteString("\n")
+}
+
+// list writes a sequence of n mappings under key. Items are written by item,
+// which is given the prefix for its first field and the indent for the rest.
+func (e *encoder) list(indent, key string, n int, item func(i int, first, rest string)) {
+	if n == 0 {
+		e.line(indent, key, "[]")
+		return
+	}
+	e.line(indent, key, "")
+	for i := 0; i < n; i++ {
+		item(i, indent+"- ", indent+"  ")
+	}
+}
+
+// fields writes the fields of a mapping, using first as the indent of the
+// first field written and rest for the others. Unknown fields are written
+// after the known field they followed when parsed. Items with no fields are
+// written as an empty mapping.
+type fields struct {
+	e           *encoder
+	first, rest string
+	written     bool
+
+	// unknown holds the unknown fields not yet written.
+	unknown []unknownField
+}
+
+func (e *encoder) fields(first, rest string, unknown *unknownFields) *fields {
+	f := &fields{e: e, first: first, rest: rest}
+	if unknown != nil {
+		f.unknown = slices.Clone(unknown.fields)
+	}
+	f.flush("")
+	return f
+}
+
+// flush writes the unknown fields that followed the known field key.
+func (f *fields) flush(key string) {
+	rest := f.unknown[:0]
+	for _, u := range f.unknown {
+		if u.after == key {
+			f.writeUnknown(u)
+		} else {
+			rest = append(rest, u)
+		}
+	}
+	f.unknown = rest
+}
+
+func (f *fields) writeUnknown(u unknownField) {
+	for i, line := range u.lines {
+		indent := f.rest
+		if i == 0 && !f.written {
+			indent = f.first
+		}
+		if line != "" {
+			f.e.w.WriteString(indent + line)
+		}
+		f.e.w.WriteString("\n")
+	}
+	f.written = true
+}
+
+func (f *fields) field(key, value string) {
+	indent := f.rest
+	if !f.written {
+		indent = f.first
+	}
+	f.e.line(indent, key, value)
+	f.written = true
+	f.flush(key)
+}
+
+func (f *fields) int(key string, v int) {
+	if v == 0 {
+		f.flush(key)
+		return
+	}
+	f.field(key, strconv.Itoa(v))
+}
+
+func (f *fields) float(key string, v float32) {
+	if v == 0 {
+		f.flush(key)
+		return
+	}
+	f.field(key, formatFloat(v))
+}
+
+func (f *fields) bool(key string, v bool) {
+	if !v {
+		f.flush(key)
+		return
+	}
+	f.field(key, "true")
+}
+
+func (f *fields) string(key, v string) {
+	f.field(key, quote(v))
+}
+
+func (f *fields) list(key string, n int, item func(i int, first, rest string)) {
+	indent := f.rest
+	if !f.written {
+		// A list cannot share the line of the item's dash, so it starts on
+		// its own line with the item's indent.
+		f.e.w.WriteString(f.first[:len(f.first)-2] + "-\n")
+	}
+	f.e.list(indent, key, n, item)
+	f.written = true
+	f.flush(key)
+}
+
+// end writes the unknown fields left over, which followed fields that were
+// omitted.
+func (f *fields) end() {
+	for _, u := range f.unknown {
+		f.writeUnknown(u)
+	}
+	f.unknown = nil
+	if !f.written {
+		f.e.w.WriteString(f.first + "{}\n")
+	}
+}
+
+func (e *encoder) encode(m *Map) {
+	top := e.fields("", "", m.unknown)
+	top.field("AudioFile", quote(m.AudioFile))
+	top.field("SongPreviewTime", strconv.Itoa(m.SongPreviewTime))
+	top.field("BackgroundFile", quote(m.BackgroundFile))
+	if m.BannerFile != "" {
+		top.field("BannerFile", quote(m.BannerFile))
+	}
+	top.field("MapId", strconv.Itoa(m.MapID))
+	top.field("MapSetId", strconv.Itoa(m.MapSetID))
+	top.field("Mode", formatMode(m.Mode))
+	top.field("Title", quote(m.Title))
+	top.field("Artist", quote(m.Artist))
+	top.field("Source", quote(m.Source))
+	top.field("Tags", quote(m.Tags))
+	top.field("Creator", quote(m.Creator))
+	top.field("DifficultyName", quote(m.DifficultyName))
+	top.field("Description", quote(m.Description))
+	if m.Genre != "" {
+		top.field("Genre", quote(m.Genre))
+	}
+	if m.LegacyLNRendering {
+		top.field("LegacyLNRendering", "true")
+	}
+	if m.BPMDoesNotAffectScrollVelocity {
+		top.field("BPMDoesNotAffectScrollVelocity", "true")
+	}
+	top.field("InitialScrollVelocity", formatFloat(m.InitialScrollVelocity))
+	if m.HasScratchKey {
+		top.field("HasScratchKey", "true")
+	}
+
+	top.list("EditorLayers", len(m.EditorLayers), func(i int, first, rest string) {
+		l := m.EditorLayers[i]
+		f := e.fields(first, rest, l.unknown)
+		f.string("Name", l.Name)
+		f.bool("Hidden", l.Hidden)
+		if l.ColorRGB != "" {
+			f.string("ColorRgb", l.ColorRGB)
+		}
+		f.end()
+	})
+
+	top.list("Bookmarks", len(m.Bookmarks), func(i int, first, rest string) {
+		b := m.Bookmarks[i]
+		f := e.fields(first, rest, b.unknown)
+		f.int("StartTime", b.StartTime)
+		if b.Note != "" {
+			f.string("Note", b.Note)
+		}
+		f.end()
+	})
+
+	top.list("CustomAudioSamples", len(m.CustomAudioSamples), func(i int, first, rest string) {
+		s := m.CustomAudioSamples[i]
+		f := e.fields(first, rest, s.unknown)
+		f.string("Path", s.Path)
+		f.bool("UnaffectedByRate", s.UnaffectedByRate)
+		f.end()
+	})
+
+	top.list("SoundEffects", len(m.SoundEffects), func(i int, first, rest string) {
+		s := m.SoundEffects[i]
+		f := e.fields(first, rest, s.unknown)
+		f.float("StartTime", s.StartTime)
+		f.int("Sample", s.Sample)
+		f.int("Volume", s.Volume)
+		f.end()
+	})
+
+	top.list("TimingPoints", len(m.TimingPoints), func(i int, first, rest string) {
+		p := m.TimingPoints[i]
+		f := e.fields(first, rest, p.unknown)
+		f.float("StartTime", p.StartTime)
+		f.float("Bpm", p.BPM)
+		if p.Signature != TimeSignatureQuadruple {
+			f.field("Signature", formatSignature(p.Signature))
+		}
+		f.bool("Hidden", p.Hidden)
+		f.end()
+	})
+
+	top.list("SliderVelocities", len(m.SliderVelocities), func(i int, first, rest string) {
+		sv := m.SliderVelocities[i]
+		f := e.fields(first, rest, sv.unknown)
+		f.float("StartTime", sv.StartTime)
+		f.float("Multiplier", sv.Multiplier)
+		f.end()
+	})
+
+	top.list("HitObjects", len(m.HitObjects), func(i int, first, rest string) {
+		h := m.HitObjects[i]
+		f := e.fields(first, rest, h.unknown)
+		f.int("StartTime", h.StartTime)
+		f.int("Lane", h.Lane)
+		f.int("EndTime", h.EndTime)
+		if h.HitSound != 0 {
+			f.field("HitSound", formatHitSound(h.HitSound))
+		}
+		f.list("KeySounds", len(h.KeySounds), func(j int, first, rest string) {
+			k := h.KeySounds[j]
+			f := e.fields(first, rest, k.unknown)
+			f.int("Sample", k.Sample)
+			f.int("Volume", k.Volume)
+			f.end()
+		})
+		f.int("EditorLayer", h.EditorLayer)
+		f.end()
+	})
+
+	top.end()
+}
+
+// formatFloat formats a number the way the game does, using the shortest
+// representation that parses back to the same 32-bit value.
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
+
+func formatMode(m quaver.GameMode) string {
+	if m == quaver.GameMode7K {
+		return "Keys7"
+	}
+	return "Keys4"
+}
+
+func formatSignature(s TimeSignature) string {
+	switch s {
+	case TimeSignatureQuadruple:
+		return "Quadruple"
+	case TimeSignatureTriple:
+		return "Triple"
+	}
+	return strconv.Itoa(int(s))
+}
+
+func formatHitSound(h HitSound) string {
+	var names []string
+	rest := h
+	for _, hs := range hitSoundNames {
+		if h&hs.sound != 0 {
+			names = append(names, hs.name)
+			rest &^= hs.sound
+		}
+	}
+	if rest != 0 {
+		return strconv.Itoa(int(h))
+	}
+	return strings.Join(names, ", ")
+}
+
+// quote returns s as a YAML scalar, leaving it plain when that is unambiguous,
+// and otherwise single quoted, or double quoted if it holds control
+// characters.
+func quote(s string) string {
+	if isPlain(s) {
+		return s
+	}
+
+	if strings.IndexFunc(s, unicode.IsControl) < 0 {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if unicode.IsControl(r) {
+				b.WriteString(`\u` + strconv.FormatInt(int64(0x10000+r), 16)[1:])
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// isPlain reports whether s can be written as a plain YAML scalar and read
+// back as the same string.
+func isPlain(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	if strings.IndexFunc(s, unicode.IsControl) >= 0 {
+		return false
+	}
+
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n", ".inf", "+.inf", ".nan":
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+	if _, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package qua
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func fixtures(t *testing.T) []string {
+	t.Helper()
+	paths, err := filepath.Glob("testdata/*.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no fixtures in testdata")
+	}
+	return paths
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for _, path := range fixtures(t) {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := Parse(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out, b) {
+				t.Errorf("Marshal does not reproduce the file:\n%s", out)
+			}
+
+			again, err := Parse(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.MD5 != m.MD5 {
+				t.Errorf("MD5 = %v after round trip, want %v", again.MD5, m.MD5)
+			}
+			if !reflect.DeepEqual(again, m) {
+				t.Errorf("Parse(Marshal(m)) = %+v, want %+v", again, m)
+			}
+		})
+	}
+}
+
+func TestMarshalKeepsUnknownFields(t *testing.T) {
+	m, err := ParseFile("testdata/unknown_fields.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the map must not lose the fields Parse does not know.
+	m.Title = "Changed"
+	m.HitObjects = m.HitObjects[1:]
+	m.TimingPoints[0].BPM = 240
+
+	out, err := Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Description: ''\nFutureSetting: 3\nInitialScrollVelocity: 1\n",
+		"FutureList:\n- Name: first\n  Value: 1\n- Name: second\nEditorLayers: []\n",
+		"- Bpm: 240\n  FutureTimingField: true\n",
+		"  KeySounds: []\n  FutureNested:\n    Depth: 2\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(string(out), "FutureFirstField") {
+		t.Errorf("output contains a field of a removed hit object:\n%s", out)
+	}
+}
+
+func TestMarshalNormalizes(t *testing.T) {
+	b, err := os.ReadFile("testdata/4k.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reordered, quoted and with Windows line endings.
+	edited := bytes.Replace(b, []byte("Title: Example Song\n"), nil, 1)
+	edited = append([]byte("Title: \"Example Song\"\n"), edited...)
+	edited = bytes.ReplaceAll(edited, []byte("\n"), []byte("\r\n"))
+
+	m, err := Parse(edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, b) {
+		t.Errorf("Marshal = \n%s\nwant\n%s", out, b)
+	}
+}
+
+// TestMarshalGameFiles checks Marshal against maps saved by the game's editor,
+// kept in testdata/game exactly as the game wrote them. The game writes maps
+// in the form Marshal produces, so each must come back byte for byte and
+// hash to its own MD5.
+func TestMarshalGameFiles(t *testing.T) {
+	paths, err := filepath.Glob("testdata/game/*.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Skip("no maps saved by the game in testdata/game")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := Parse(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out, b) {
+				line := 1 + bytes.Count(b[:commonPrefix(out, b)], []byte("\n"))
+				t.Errorf("Marshal differs from the game's file from line %d", line)
+			}
+
+			alt, err := AlternativeMD5(bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if alt != m.MD5 {
+				t.Errorf("AlternativeMD5 = %v, want the file's own MD5 %v", alt, m.MD5)
+			}
+		})
+	}
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b.
+func commonPrefix(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,137 @@
+package qua
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// MD5 returns the MD5 hash of a .qua file, as found in quaver.Map.MD5.
+func MD5(r io.Reader) (string, error) {
+	h := md5.New()
+	_, err := io.Copy(h, r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// AlternativeMD5 returns the MD5 hash of a .qua file after normalizing it,
+// for comparison with quaver.Map.AlternativeMd5. The file is parsed and
+// written back with Marshal, so files that differ only in line endings,
+// field order, quoting or number formatting share the same hash.
+//
+// The server computes its alternative hash from the map as the game writes
+// it, so the two only agree when Marshal reproduces the game's output for the
+// map. Use it as a fallback lookup, as ResolveDir does, and treat a miss as
+// inconclusive.
+func AlternativeMD5(r io.Reader) (string, error) {
+	m, err := Decode(r)
+	if err != nil {
+		return "", err
+	}
+	return Hash(m)
+}
+
+// Hashes returns both the MD5 and the alternative MD5 of a .qua file.
+func Hashes(r io.Reader) (sum, alt string, err error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", "", err
+	}
+
+	m, err := Parse(b)
+	if err != nil {
+		return "", "", err
+	}
+
+	alt, err = Hash(m)
+	if err != nil {
+		return "", "", err
+	}
+	return m.MD5, alt, nil
+}
+
+// HashFile returns both the MD5 and the alternative MD5 of the .qua file at
+// path.
+func HashFile(path string) (sum, alt string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	return Hashes(f)
+}
+
+// Resolved is a local .qua file matched to its map on the Quaver API.
+type Resolved struct {
+	Path           string
+	MD5            string
+	AlternativeMD5 string
+
+	// Map is the matching map, or nil if none was found.
+	Map *quaver.Map
+
+	// Err is the error that stopped the file from being hashed or looked up.
+	// A map that does not exist on the API is not an error.
+	Err error
+}
+
+// ResolveDir walks dir for .qua files and looks up each of them with
+// Maps.GetByMD5, trying the alternative MD5 when the file's own hash is not
+// found. Per-file failures are reported in Resolved.Err; the returned error is
+// only set if walking dir fails or ctx is done.
+func ResolveDir(ctx context.Context, client *quaver.Client, dir string) ([]*Resolved, error) {
+	var results []*Resolved
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".qua") {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		results = append(results, resolve(ctx, client, path))
+		return nil
+	})
+
+	return results, err
+}
+
+func resolve(ctx context.Context, client *quaver.Client, path string) *Resolved {
+	r := &Resolved{Path: path}
+
+	r.MD5, r.AlternativeMD5, r.Err = HashFile(path)
+	if r.Err != nil {
+		return r
+	}
+
+	for _, hash := range []string{r.MD5, r.AlternativeMD5} {
+		m, _, err := client.Maps.GetByMD5(ctx, hash)
+		if quaver.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			r.Err = err
+			return r
+		}
+		if m != nil {
+			r.Map = m
+			return r
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,75 @@
+package qua
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+const bom = "\xef\xbb\xbf"
+
+func TestParseMD5IncludesBOM(t *testing.T) {
+	b, err := os.ReadFile("testdata/4k.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	withBOM := append([]byte(bom), b...)
+
+	m, err := Parse(withBOM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := MD5(bytes.NewReader(withBOM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MD5 != want {
+		t.Errorf("Parse MD5 = %v, want %v", m.MD5, want)
+	}
+
+	sum, _, err := Hashes(bytes.NewReader(withBOM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != want {
+		t.Errorf("Hashes MD5 = %v, want %v", sum, want)
+	}
+
+	plain, err := MD5(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum == plain {
+		t.Errorf("MD5 with and without BOM are both %v", sum)
+	}
+}
+
+func TestAlternativeMD5(t *testing.T) {
+	b, err := os.ReadFile("testdata/4k.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The fixture is in Marshal's format, so its alternative hash is its own.
+	want, err := MD5(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variants := map[string][]byte{
+		"original": b,
+		"bom":      append([]byte(bom), b...),
+		"crlf":     bytes.ReplaceAll(b, []byte("\n"), []byte("\r\n")),
+		"quoted":   bytes.Replace(b, []byte("Title: Example Song"), []byte(`Title: "Example Song"`), 1),
+	}
+	for name, v := range variants {
+		got, err := AlternativeMD5(bytes.NewReader(v))
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%v: AlternativeMD5 = %v, want %v", name, got, want)
+		}
+	}
+}
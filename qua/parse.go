@@ -0,0 +1,581 @@
+package qua
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// Error describes a problem with a field of a .qua file.
+type Error struct {
+	// Line is the line of the field in the file, or zero if unknown.
+	Line int
+
+	// Field is the path of the field, such as "HitObjects[12].Lane".
+	Field string
+
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("qua: line %d: %v: %v", e.Line, e.Field, e.Msg)
+	}
+	return fmt.Sprintf("qua: %v: %v", e.Field, e.Msg)
+}
+
+// Parse parses the contents of a .qua file.
+func Parse(b []byte) (*Map, error) {
+	// The hash is of the file as stored, so it is taken before anything is
+	// stripped.
+	sum := md5.Sum(b)
+
+	// Files saved on Windows may start with a byte order mark.
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+
+	var doc yaml.Node
+	err := yaml.Unmarshal(b, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("qua: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, &Error{Line: 1, Field: "(root)", Msg: "empty file"}
+	}
+
+	d := &decoder{lines: make(map[string]int), src: strings.Split(string(b), "\n")}
+	m := d.decodeMap(doc.Content[0])
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	m.MD5 = hex.EncodeToString(sum[:])
+
+	err = m.validate(d.lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Decode reads and parses a .qua file from r.
+func Decode(r io.Reader) (*Map, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// ParseFile reads and parses the .qua file at path.
+func ParseFile(path string) (*Map, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// decoder converts YAML nodes into a Map, keeping the first error and the
+// line of every field for validation.
+type decoder struct {
+	err   error
+	lines map[string]int
+
+	// src holds the lines of the file, to keep unknown fields as written.
+	src []string
+}
+
+func (d *decoder) fail(n *yaml.Node, field, format string, args ...any) {
+	if field == "" {
+		field = "(root)"
+	}
+	if d.err == nil {
+		d.err = &Error{Line: n.Line, Field: field, Msg: fmt.Sprintf(format, args...)}
+	}
+}
+
+// fields calls fn for every key and value of a mapping node. Keys fn does
+// not know, which it reports by returning false, are kept in unknown as they
+// were written.
+func (d *decoder) fields(n *yaml.Node, path string, unknown **unknownFields, fn func(key string, v *yaml.Node, field string) bool) {
+	if n.Kind != yaml.MappingNode {
+		d.fail(n, path, "expected a mapping")
+		return
+	}
+
+	d.lines[path] = n.Line
+	after := ""
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		key := k.Value
+		field := key
+		if path != "" {
+			field = path + "." + key
+		}
+		d.lines[field] = v.Line
+		if fn(key, v, field) {
+			after = key
+			continue
+		}
+
+		if *unknown == nil {
+			*unknown = &unknownFields{}
+		}
+		f := unknownField{key: key, after: after}
+		end := lastLine(v)
+		if i+2 < len(n.Content) {
+			end = n.Content[i+2].Line - 1
+		}
+		if n.Style&yaml.FlowStyle != 0 || end < k.Line {
+			f.lines = []string{key + ": " + flow(v)}
+		} else {
+			f.lines = d.source(k, end)
+		}
+		(*unknown).fields = append((*unknown).fields, f)
+	}
+}
+
+// source returns the lines from key k to end, without the indent of k.
+func (d *decoder) source(k *yaml.Node, end int) []string {
+	end = min(end, len(d.src))
+	for end > k.Line && strings.TrimSpace(d.src[end-1]) == "" {
+		end--
+	}
+
+	indent := k.Column - 1
+	lines := make([]string, 0, end-k.Line+1)
+	for i, line := range d.src[k.Line-1 : end] {
+		line = strings.TrimSuffix(line, "\r")
+		if i == 0 {
+			line = line[min(indent, len(line)):]
+		} else {
+			line = line[min(indent, len(line)-len(strings.TrimLeft(line, " "))):]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// lastLine returns the last line of the file n takes up.
+func lastLine(n *yaml.Node) int {
+	line := n.Line
+	if n.Kind == yaml.ScalarNode && n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		line += strings.Count(strings.TrimSuffix(n.Value, "\n"), "\n") + 1
+	}
+	for _, c := range n.Content {
+		line = max(line, lastLine(c))
+	}
+	return line
+}
+
+// flow returns n written on a single line.
+func flow(n *yaml.Node) string {
+	c := *n
+	c.Style |= yaml.FlowStyle
+	b, err := yaml.Marshal(&c)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// items calls fn for every item of a sequence node.
+func (d *decoder) items(n *yaml.Node, path string, fn func(v *yaml.Node, field string)) {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+	if n.Kind != yaml.SequenceNode {
+		d.fail(n, path, "expected a list")
+		return
+	}
+	for i, v := range n.Content {
+		field := fmt.Sprintf("%v[%d]", path, i)
+		d.lines[field] = v.Line
+		fn(v, field)
+	}
+}
+
+func (d *decoder) scalar(n *yaml.Node, field string) (string, bool) {
+	if n.Kind != yaml.ScalarNode {
+		d.fail(n, field, "expected a value")
+		return "", false
+	}
+	if n.Tag == "!!null" {
+		return "", false
+	}
+	return n.Value, true
+}
+
+func (d *decoder) string(n *yaml.Node, field string) string {
+	s, _ := d.scalar(n, field)
+	return s
+}
+
+func (d *decoder) int(n *yaml.Node, field string) int {
+	s, ok := d.scalar(n, field)
+	if !ok {
+		return 0
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		d.fail(n, field, "invalid integer %q", s)
+	}
+	return i
+}
+
+func (d *decoder) float(n *yaml.Node, field string) float32 {
+	s, ok := d.scalar(n, field)
+	if !ok {
+		return 0
+	}
+	f, err := strconv.ParseFloat(s, 32)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		d.fail(n, field, "invalid number %q", s)
+	}
+	return float32(f)
+}
+
+func (d *decoder) bool(n *yaml.Node, field string) bool {
+	s, ok := d.scalar(n, field)
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		d.fail(n, field, "invalid boolean %q", s)
+	}
+	return b
+}
+
+func (d *decoder) mode(n *yaml.Node, field string) quaver.GameMode {
+	s, _ := d.scalar(n, field)
+	switch s {
+	case "Keys4", "1":
+		return quaver.GameMode4K
+	case "Keys7", "2":
+		return quaver.GameMode7K
+	}
+	d.fail(n, field, "unknown game mode %q", s)
+	return 0
+}
+
+func (d *decoder) signature(n *yaml.Node, field string) TimeSignature {
+	s, ok := d.scalar(n, field)
+	if !ok {
+		return TimeSignatureQuadruple
+	}
+	switch s {
+	case "Quadruple":
+		return TimeSignatureQuadruple
+	case "Triple":
+		return TimeSignatureTriple
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		d.fail(n, field, "unknown time signature %q", s)
+	}
+	return TimeSignature(i)
+}
+
+var hitSoundNames = []struct {
+	name  string
+	sound HitSound
+}{
+	{"Normal", HitSoundNormal},
+	{"Whistle", HitSoundWhistle},
+	{"Finish", HitSoundFinish},
+	{"Clap", HitSoundClap},
+}
+
+func (d *decoder) hitSound(n *yaml.Node, field string) HitSound {
+	s, ok := d.scalar(n, field)
+	if !ok {
+		return 0
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		return HitSound(i)
+	}
+
+	var sound HitSound
+next:
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		for _, hs := range hitSoundNames {
+			if hs.name == name {
+				sound |= hs.sound
+				continue next
+			}
+		}
+		d.fail(n, field, "unknown hit sound %q", name)
+	}
+	return sound
+}
+
+func (d *decoder) decodeMap(n *yaml.Node) *Map {
+	m := &Map{InitialScrollVelocity: 1}
+
+	d.fields(n, "", &m.unknown, func(key string, v *yaml.Node, field string) bool {
+		switch key {
+		case "AudioFile":
+			m.AudioFile = d.string(v, field)
+		case "SongPreviewTime":
+			m.SongPreviewTime = d.int(v, field)
+		case "BackgroundFile":
+			m.BackgroundFile = d.string(v, field)
+		case "BannerFile":
+			m.BannerFile = d.string(v, field)
+		case "MapId":
+			m.MapID = d.int(v, field)
+		case "MapSetId":
+			m.MapSetID = d.int(v, field)
+		case "Mode":
+			m.Mode = d.mode(v, field)
+		case "Title":
+			m.Title = d.string(v, field)
+		case "Artist":
+			m.Artist = d.string(v, field)
+		case "Source":
+			m.Source = d.string(v, field)
+		case "Tags":
+			m.Tags = d.string(v, field)
+		case "Creator":
+			m.Creator = d.string(v, field)
+		case "DifficultyName":
+			m.DifficultyName = d.string(v, field)
+		case "Description":
+			m.Description = d.string(v, field)
+		case "Genre":
+			m.Genre = d.string(v, field)
+		case "LegacyLNRendering":
+			m.LegacyLNRendering = d.bool(v, field)
+		case "BPMDoesNotAffectScrollVelocity":
+			m.BPMDoesNotAffectScrollVelocity = d.bool(v, field)
+		case "InitialScrollVelocity":
+			m.InitialScrollVelocity = d.float(v, field)
+		case "HasScratchKey":
+			m.HasScratchKey = d.bool(v, field)
+		case "EditorLayers":
+			d.items(v, field, func(v *yaml.Node, field string) {
+				var l EditorLayer
+				d.fields(v, field, &l.unknown, func(key string, v *yaml.Node, field string) bool {
+					switch key {
+					case "Name":
+						l.Name = d.string(v, field)
+					case "Hidden":
+						l.Hidden = d.bool(v, field)
+					case "ColorRgb":
+						l.ColorRGB = d.string(v, field)
+					default:
+						return false
+					}
+					return true
+				})
+				m.EditorLayers = append(m.EditorLayers, l)
+			})
+		case "Bookmarks":
+			d.items(v, field, func(v *yaml.Node, field string) {
+				var b Bookmark
+				d.fields(v, field, &b.unknown, func(key string, v *yaml.Node, field string) bool {
+					switch key {
+					case "StartTime":
+						b.StartTime = d.int(v, field)
+					case "Note":
+						b.Note = d.string(v, field)
+					default:
+						return false
+					}
+					return true
+				})
+				m.Bookmarks = append(m.Bookmarks, b)
+			})
+		case "CustomAudioSamples":
+			d.items(v, field, func(v *yaml.Node, field string) {
+				var s CustomAudioSample
+				d.fields(v, field, &s.unknown, func(key string, v *yaml.Node, field string) bool {
+					switch key {
+					case "Path":
+						s.Path = d.string(v, field)
+					case "UnaffectedByRate":
+						s.UnaffectedByRate = d.bool(v, field)
+					default:
+						return false
+					}
+					return true
+				})
+				m.CustomAudioSamples = append(m.CustomAudioSamples, s)
+			})
+		case "SoundEffects":
+			d.items(v, field, func(v *yaml.Node, field string) {
+				var e SoundEffect
+				d.fields(v, field, &e.unknown, func(key string, v *yaml.Node, field string) bool {
+					switch key {
+					case "StartTime":
+						e.StartTime = d.float(v, field)
+					case "Sample":
+						e.Sample = d.int(v, field)
+					case "Volume":
+						e.Volume = d.int(v, field)
+					default:
+						return false
+					}
+					return true
+				})
+				m.SoundEffects = append(m.SoundEffects, e)
+			})
+		case "TimingPoints":
+			d.items(v, field, func(v *yaml.Node, field string) {
+				p := TimingPoint{Signature: TimeSignatureQuadruple}
+				d.fields(v, field, &p.unknown, func(key string, v *yaml.Node, field string) bool {
+					switch key {
+					case "StartTime":
+						p.StartTime = d.float(v, field)
+					case "Bpm":
+						p.BPM = d.float(v, field)
+					case "Signature":
+						p.Signature = d.signature(v, field)
+					case "Hidden":
+						p.Hidden = d.bool(v, field)
+					default:
+						return false
+					}
+					return true
+				})
+				m.TimingPoints = append(m.TimingPoints, p)
+			})
+		case "SliderVelocities":
+			d.items(v, field, func(v *yaml.Node, field string) {
+				var sv SliderVelocity
+				d.fields(v, field, &sv.unknown, func(key string, v *yaml.Node, field string) bool {
+					switch key {
+					case "StartTime":
+						sv.StartTime = d.float(v, field)
+					case "Multiplier":
+						sv.Multiplier = d.float(v, field)
+					default:
+						return false
+					}
+					return true
+				})
+				m.SliderVelocities = append(m.SliderVelocities, sv)
+			})
+		case "HitObjects":
+			d.items(v, field, func(v *yaml.Node, field string) {
+				var h HitObject
+				d.fields(v, field, &h.unknown, func(key string, v *yaml.Node, field string) bool {
+					switch key {
+					case "StartTime":
+						h.StartTime = d.int(v, field)
+					case "Lane":
+						h.Lane = d.int(v, field)
+					case "EndTime":
+						h.EndTime = d.int(v, field)
+					case "HitSound":
+						h.HitSound = d.hitSound(v, field)
+					case "EditorLayer":
+						h.EditorLayer = d.int(v, field)
+					case "KeySounds":
+						d.items(v, field, func(v *yaml.Node, field string) {
+							var k KeySound
+							d.fields(v, field, &k.unknown, func(key string, v *yaml.Node, field string) bool {
+								switch key {
+								case "Sample":
+									k.Sample = d.int(v, field)
+								case "Volume":
+									k.Volume = d.int(v, field)
+								default:
+									return false
+								}
+								return true
+							})
+							h.KeySounds = append(h.KeySounds, k)
+						})
+					default:
+						return false
+					}
+					return true
+				})
+				m.HitObjects = append(m.HitObjects, h)
+			})
+		default:
+			return false
+		}
+		return true
+	})
+
+	if _, ok := d.lines["Mode"]; !ok {
+		d.fail(n, "Mode", "missing game mode")
+	}
+
+	return m
+}
+
+// Validate checks that the map is consistent, such as that every hit object is
+// in a valid lane and long notes end after they start.
+func (m *Map) Validate() error {
+	return m.validate(nil)
+}
+
+func (m *Map) validate(lines map[string]int) error {
+	fail := func(field, format string, args ...any) error {
+		return &Error{Line: lines[field], Field: field, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	if m.Mode != quaver.GameMode4K && m.Mode != quaver.GameMode7K {
+		return fail("Mode", "unknown game mode %d", m.Mode)
+	}
+
+	for i, p := range m.TimingPoints {
+		field := fmt.Sprintf("TimingPoints[%d]", i)
+		if math.IsNaN(float64(p.BPM)) || math.IsInf(float64(p.BPM), 0) {
+			return fail(field+".Bpm", "invalid BPM")
+		}
+		if p.Signature <= 0 {
+			return fail(field+".Signature", "invalid time signature %d", p.Signature)
+		}
+	}
+
+	for i, sv := range m.SliderVelocities {
+		if math.IsNaN(float64(sv.Multiplier)) || math.IsInf(float64(sv.Multiplier), 0) {
+			return fail(fmt.Sprintf("SliderVelocities[%d].Multiplier", i), "invalid multiplier")
+		}
+	}
+
+	for i, e := range m.SoundEffects {
+		if e.Sample < 1 || e.Sample > len(m.CustomAudioSamples) {
+			return fail(fmt.Sprintf("SoundEffects[%d].Sample", i), "sample %d does not exist", e.Sample)
+		}
+	}
+
+	keys := m.KeyCount()
+	for i, h := range m.HitObjects {
+		field := fmt.Sprintf("HitObjects[%d]", i)
+		if h.Lane < 1 || h.Lane > keys {
+			return fail(field+".Lane", "lane %d outside of 1-%d", h.Lane, keys)
+		}
+		if h.EndTime != 0 && h.EndTime <= h.StartTime {
+			return fail(field+".EndTime", "long note ends at %d before it starts at %d", h.EndTime, h.StartTime)
+		}
+		if h.EditorLayer < 0 || h.EditorLayer > len(m.EditorLayers) {
+			return fail(field+".EditorLayer", "layer %d does not exist", h.EditorLayer)
+		}
+		for j, k := range h.KeySounds {
+			if k.Sample < 1 || k.Sample > len(m.CustomAudioSamples) {
+				return fail(fmt.Sprintf("%v.KeySounds[%d].Sample", field, j), "sample %d does not exist", k.Sample)
+			}
+		}
+	}
+
+	return nil
+}
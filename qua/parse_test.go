@@ -0,0 +1,131 @@
+package qua
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		line  int
+		field string
+	}{
+		{"empty", "", 1, "(root)"},
+		{"not a mapping", "- 1\n- 2\n", 1, "(root)"},
+		{"missing mode", "Title: x\nHitObjects: []\n", 1, "Mode"},
+		{"unknown mode", "Title: x\nMode: Keys5\n", 2, "Mode"},
+		{"integer", "Mode: Keys4\nMapId: twelve\n", 2, "MapId"},
+		{"boolean", "Mode: Keys4\nHasScratchKey: maybe\n", 2, "HasScratchKey"},
+		{"list", "Mode: Keys4\nHitObjects: 3\n", 2, "HitObjects"},
+		{
+			"hit object field",
+			"Mode: Keys4\nHitObjects:\n- StartTime: 0\n  Lane: 1\n- StartTime: 10\n  Lane: one\n",
+			6, "HitObjects[1].Lane",
+		},
+		{
+			"hit sound",
+			"Mode: Keys4\nHitObjects:\n- StartTime: 0\n  Lane: 1\n  HitSound: Bang\n",
+			5, "HitObjects[0].HitSound",
+		},
+		{
+			"time signature",
+			"Mode: Keys4\nTimingPoints:\n- StartTime: 0\n  Bpm: 120\n  Signature: Sextuple\n",
+			5, "TimingPoints[0].Signature",
+		},
+		{
+			"slider velocity",
+			"Mode: Keys4\nSliderVelocities:\n- StartTime: 0\n  Multiplier: fast\n",
+			4, "SliderVelocities[0].Multiplier",
+		},
+		{
+			"key sound",
+			"Mode: Keys4\nHitObjects:\n- StartTime: 0\n  Lane: 1\n  KeySounds:\n  - Sample: 1\n    Volume: loud\n",
+			7, "HitObjects[0].KeySounds[0].Volume",
+		},
+
+		// Validation reports the line of the field it rejects.
+		{
+			"lane out of range",
+			"Mode: Keys4\nHitObjects:\n- StartTime: 0\n  Lane: 1\n- StartTime: 10\n  Lane: 5\n",
+			6, "HitObjects[1].Lane",
+		},
+		{
+			"scratch lane without a scratch key",
+			"Mode: Keys7\nHitObjects:\n- StartTime: 0\n  Lane: 8\n",
+			4, "HitObjects[0].Lane",
+		},
+		{
+			"long note ends early",
+			"Mode: Keys4\nHitObjects:\n- StartTime: 100\n  Lane: 1\n  EndTime: 50\n",
+			5, "HitObjects[0].EndTime",
+		},
+		{
+			"editor layer",
+			"Mode: Keys4\nEditorLayers: []\nHitObjects:\n- StartTime: 0\n  Lane: 1\n  EditorLayer: 2\n",
+			6, "HitObjects[0].EditorLayer",
+		},
+		{
+			"sound effect sample",
+			"Mode: Keys4\nCustomAudioSamples: []\nSoundEffects:\n- StartTime: 0\n  Sample: 1\n  Volume: 100\n",
+			5, "SoundEffects[0].Sample",
+		},
+		{
+			"key sound sample",
+			"Mode: Keys4\nHitObjects:\n- StartTime: 0\n  Lane: 1\n  KeySounds:\n  - Sample: 3\n    Volume: 100\n",
+			6, "HitObjects[0].KeySounds[0].Sample",
+		},
+		{
+			"zero time signature",
+			"Mode: Keys4\nTimingPoints:\n- StartTime: 0\n  Bpm: 120\n  Signature: 0\n",
+			5, "TimingPoints[0].Signature",
+		},
+		{
+			"infinite bpm",
+			"Mode: Keys4\nTimingPoints:\n- StartTime: 0\n  Bpm: .inf\n",
+			4, "TimingPoints[0].Bpm",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.src))
+			var qerr *Error
+			if !errors.As(err, &qerr) {
+				t.Fatalf("Parse error = %v, want a *qua.Error", err)
+			}
+			if qerr.Line != tt.line || qerr.Field != tt.field {
+				t.Errorf("Parse error at line %d, field %q, want line %d, field %q (%v)", qerr.Line, qerr.Field, tt.line, tt.field, err)
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("Error() = %q, want the field in it", err.Error())
+			}
+		})
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	// YAML that does not parse at all is reported by the YAML decoder.
+	_, err := Parse([]byte("Mode: Keys4\nHitObjects: [\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "qua: ") {
+		t.Errorf("Parse error = %v, want a qua: error", err)
+	}
+}
+
+func TestValidateWithoutLines(t *testing.T) {
+	m, err := ParseFile("testdata/4k.qua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.HitObjects[2].Lane = 9
+
+	// A map built in code has no lines to report.
+	var qerr *Error
+	if err := m.Validate(); !errors.As(err, &qerr) || qerr.Line != 0 || qerr.Field != "HitObjects[2].Lane" {
+		t.Errorf("Validate = %v, want an error for HitObjects[2].Lane without a line", err)
+	}
+	if got := qerr.Error(); strings.Contains(got, "line") {
+		t.Errorf("Error() = %q, want no line", got)
+	}
+}
@@ -0,0 +1,257 @@
+// Package qua reads Quaver .qua map files.
+package qua
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// Map is a parsed .qua file.
+type Map struct {
+	AudioFile       string
+	SongPreviewTime int
+	BackgroundFile  string
+	BannerFile      string
+	MapID           int
+	MapSetID        int
+	Mode            quaver.GameMode
+	Title           string
+	Artist          string
+	Source          string
+	Tags            string
+	Creator         string
+	DifficultyName  string
+	Description     string
+	Genre           string
+
+	LegacyLNRendering              bool
+	BPMDoesNotAffectScrollVelocity bool
+	InitialScrollVelocity          float32
+	HasScratchKey                  bool
+
+	EditorLayers       []EditorLayer
+	Bookmarks          []Bookmark
+	CustomAudioSamples []CustomAudioSample
+	SoundEffects       []SoundEffect
+	TimingPoints       []TimingPoint
+	SliderVelocities   []SliderVelocity
+	HitObjects         []HitObject
+
+	// MD5 is the hex encoded MD5 hash of the file the map was parsed from. It
+	// is empty for maps that were not parsed.
+	MD5 string
+
+	unknown *unknownFields
+}
+
+// unknownFields holds the fields of a mapping that Parse does not know, such
+// as ones added by newer versions of the game, so Marshal can write them back.
+type unknownFields struct {
+	fields []unknownField
+}
+
+type unknownField struct {
+	key string
+	// after is the known key the field followed, or empty if it came first.
+	after string
+	// lines holds the field as written, without the indent of its key.
+	lines []string
+}
+
+// EditorLayer is a layer hit objects can be placed on in the editor.
+type EditorLayer struct {
+	Name     string
+	Hidden   bool
+	ColorRGB string
+
+	unknown *unknownFields
+}
+
+// Bookmark is a note placed on the timeline in the editor.
+type Bookmark struct {
+	StartTime int
+	Note      string
+
+	unknown *unknownFields
+}
+
+// CustomAudioSample is an audio file used by key sounds and sound effects.
+type CustomAudioSample struct {
+	Path             string
+	UnaffectedByRate bool
+
+	unknown *unknownFields
+}
+
+// SoundEffect plays a custom audio sample at a point in time.
+type SoundEffect struct {
+	StartTime float32
+	// Sample is the 1-based index of the sample in CustomAudioSamples.
+	Sample int
+	Volume int
+
+	unknown *unknownFields
+}
+
+// TimeSignature is the number of beats in a measure.
+type TimeSignature int
+
+const (
+	TimeSignatureQuadruple TimeSignature = 4
+	TimeSignatureTriple    TimeSignature = 3
+)
+
+// TimingPoint changes the BPM of the map.
+type TimingPoint struct {
+	StartTime float32
+	BPM       float32
+	Signature TimeSignature
+	Hidden    bool
+
+	unknown *unknownFields
+}
+
+// SliderVelocity changes the scroll speed of the map.
+type SliderVelocity struct {
+	StartTime  float32
+	Multiplier float32
+
+	unknown *unknownFields
+}
+
+// HitSound is a set of default sounds played when a hit object is hit.
+type HitSound int
+
+const (
+	HitSoundNormal HitSound = 1 << iota
+	HitSoundWhistle
+	HitSoundFinish
+	HitSoundClap
+)
+
+// KeySound plays a custom audio sample when a hit object is hit.
+type KeySound struct {
+	// Sample is the 1-based index of the sample in CustomAudioSamples.
+	Sample int
+	Volume int
+
+	unknown *unknownFields
+}
+
+// HitObject is a note in the map.
+type HitObject struct {
+	StartTime int
+	// Lane is the 1-based lane of the note.
+	Lane int
+	// EndTime is the end of a long note, or zero for a normal note.
+	EndTime     int
+	HitSound    HitSound
+	KeySounds   []KeySound
+	EditorLayer int
+
+	unknown *unknownFields
+}
+
+// IsLongNote reports whether the hit object is a long note.
+func (h *HitObject) IsLongNote() bool {
+	return h.EndTime > 0
+}
+
+// KeyCount returns the number of lanes in the map, including the scratch lane.
+func (m *Map) KeyCount() int {
+	n := 4
+	if m.Mode == quaver.GameMode7K {
+		n = 7
+	}
+	if m.HasScratchKey {
+		n++
+	}
+	return n
+}
+
+// Length returns the time at which the last hit object ends.
+func (m *Map) Length() int {
+	length := 0
+	for _, h := range m.HitObjects {
+		length = max(length, h.StartTime, h.EndTime)
+	}
+	return length
+}
+
+// CommonBPM returns the BPM that is in effect for the longest part of the map.
+func (m *Map) CommonBPM() float32 {
+	if len(m.TimingPoints) == 0 {
+		return 0
+	}
+
+	points := slices.Clone(m.TimingPoints)
+	slices.SortStableFunc(points, func(a, b TimingPoint) int {
+		return cmp.Compare(a.StartTime, b.StartTime)
+	})
+
+	end := float32(m.Length())
+	durations := make(map[float32]float32)
+	for i := len(points) - 1; i >= 0; i-- {
+		p := points[i]
+		if p.StartTime > end {
+			continue
+		}
+		durations[p.BPM] += end - p.StartTime
+		end = p.StartTime
+	}
+
+	var bpm, longest float32 = points[0].BPM, -1
+	for b, d := range durations {
+		if d > longest || (d == longest && b < bpm) {
+			bpm, longest = b, d
+		}
+	}
+	return bpm
+}
+
+// Metadata returns the map's metadata in the form returned by the Quaver API,
+// with the fields that can be derived from the file filled in.
+func (m *Map) Metadata() *quaver.Map {
+	var normal, long int
+	for _, h := range m.HitObjects {
+		if h.IsLongNote() {
+			long++
+		} else {
+			normal++
+		}
+	}
+
+	var lnPercent float64
+	if total := normal + long; total > 0 {
+		lnPercent = math.Round(float64(long)/float64(total)*100*100) / 100
+	}
+
+	return &quaver.Map{
+		ID:                   m.MapID,
+		MapsetID:             m.MapSetID,
+		MD5:                  m.MD5,
+		CreatorUsername:      m.Creator,
+		GameMode:             int(m.Mode),
+		Artist:               m.Artist,
+		Title:                m.Title,
+		Source:               m.Source,
+		Tags:                 m.Tags,
+		Description:          m.Description,
+		DifficultyName:       m.DifficultyName,
+		Length:               m.Length(),
+		BPM:                  float64(m.CommonBPM()),
+		CountHitobjectNormal: normal,
+		CountHitobjectLong:   long,
+		LongNotePercentage:   lnPercent,
+		MaxCombo:             normal + long*2,
+	}
+}
+
+// Matches reports whether the map is the one described by the API map,
+// comparing both of its MD5 hashes.
+func (m *Map) Matches(apiMap *quaver.Map) bool {
+	return m.MD5 != "" && (m.MD5 == apiMap.MD5 || m.MD5 == apiMap.AlternativeMd5)
+}
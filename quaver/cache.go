@@ -0,0 +1,193 @@
+package quaver
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores the bodies of successful API responses, keyed by request URL.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for key, if it exists and has not expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores value for key. A non-positive ttl means the value never expires.
+	Set(key string, value []byte, ttl time.Duration)
+
+	// Delete removes the value stored for key.
+	Delete(key string)
+}
+
+// defaultCacheTTL is used for endpoints that match none of the cache rules.
+const defaultCacheTTL = time.Minute
+
+type cacheRule struct {
+	pattern string
+	ttl     time.Duration
+}
+
+// defaultCacheRules are the TTLs used for endpoints unless overridden with
+// WithCacheTTL. The rule with the most path segments wins, and later rules win
+// ties.
+var defaultCacheRules = []cacheRule{
+	{"map", time.Hour},
+	{"mapset", 10 * time.Minute},
+	{"mapset/ranked", time.Hour},
+	{"mapset/offsets", time.Hour},
+	{"user", 5 * time.Minute},
+	{"user/*/activity", time.Minute},
+	{"user/*/scores/*/recent", 30 * time.Second},
+	{"scores", 30 * time.Second},
+	{"leaderboard", time.Minute},
+	{"server/stats", time.Minute},
+	{"download/map", time.Hour},
+}
+
+// WithCache caches successful responses in cache, using per-endpoint TTLs.
+func WithCache(cache Cache) Option {
+	return func(c *Client) error {
+		c.cache = cache
+		return nil
+	}
+}
+
+// WithCacheTTL sets the TTL of cached responses for endpoints matching
+// pattern. A pattern is a slash separated list of path segments, where "*"
+// matches any single segment, and it matches every endpoint whose path starts
+// with those segments, such as "mapset/ranked" or "user/*/scores/*/recent".
+// The pattern with the most segments wins, and later patterns win ties. A
+// non-positive ttl disables caching for matching endpoints.
+func WithCacheTTL(pattern string, ttl time.Duration) Option {
+	return func(c *Client) error {
+		c.cacheRules = append(c.cacheRules, cacheRule{pattern, ttl})
+		return nil
+	}
+}
+
+// cacheTTL returns the TTL for the endpoint at url, relative to the base URL.
+func (c *Client) cacheTTL(url string) time.Duration {
+	path, _, _ := strings.Cut(url, "?")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	ttl := defaultCacheTTL
+	matched := -1
+	for _, rule := range c.cacheRules {
+		n, ok := matchSegments(rule.pattern, segments)
+		if ok && n >= matched {
+			ttl = rule.ttl
+			matched = n
+		}
+	}
+	return ttl
+}
+
+// matchSegments reports whether pattern matches the leading path segments,
+// and the number of segments it matched.
+func matchSegments(pattern string, segments []string) (int, bool) {
+	parts := strings.Split(strings.Trim(pattern, "/"), "/")
+	if len(parts) > len(segments) {
+		return 0, false
+	}
+	for i, p := range parts {
+		if p != "*" && p != segments[i] {
+			return 0, false
+		}
+	}
+	return len(parts), true
+}
+
+type bypassCacheKey struct{}
+
+// BypassCache returns a context that makes requests skip the client's cache.
+// Responses to such requests are still stored in the cache.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func isCacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+// MemoryCache is an in-memory Cache that evicts the least recently used
+// entries once it is full.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns a MemoryCache holding at most capacity entries.
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: max(capacity, 1),
+		ll:       list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*memoryCacheEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		m.remove(el)
+		return nil, false
+	}
+
+	m.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	if el, ok := m.entries[key]; ok {
+		e := el.Value.(*memoryCacheEntry)
+		e.value = value
+		e.expires = expires
+		m.ll.MoveToFront(el)
+		return
+	}
+
+	m.entries[key] = m.ll.PushFront(&memoryCacheEntry{key, value, expires})
+	for m.ll.Len() > m.capacity {
+		m.remove(m.ll.Back())
+	}
+}
+
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+}
+
+func (m *MemoryCache) remove(el *list.Element) {
+	m.ll.Remove(el)
+	delete(m.entries, el.Value.(*memoryCacheEntry).key)
+}
@@ -0,0 +1,126 @@
+package quaver_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+const userBody = `{"user": {"id": 1, "username": "Swan"}}`
+
+func TestCache(t *testing.T) {
+	client, mux := setup(t, quaver.WithCache(quaver.NewMemoryCache(100)))
+	n := serve(mux, "GET /v2/user/1", http.StatusOK, userBody)
+
+	for i := range 2 {
+		user, resp, err := client.Users.GetByID(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Username != "Swan" {
+			t.Errorf("GetByID = %+v, want Swan", user.UserCompact)
+		}
+		if resp.Cached != (i == 1) {
+			t.Errorf("request %d: Cached = %v", i, resp.Cached)
+		}
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+
+	// Bypassing the cache fetches again.
+	_, resp, err := client.Users.GetByID(quaver.BypassCache(ctx), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Cached {
+		t.Error("BypassCache response was cached")
+	}
+	if got := n.Load(); got != 2 {
+		t.Errorf("sent %d requests, want 2", got)
+	}
+}
+
+func TestCacheSkipsErrors(t *testing.T) {
+	client, mux := setup(t, quaver.WithCache(quaver.NewMemoryCache(100)))
+	n := serve(mux, "GET /v2/map/1", http.StatusNotFound, `{"error": "Map not found"}`)
+
+	for range 2 {
+		_, _, err := client.Maps.GetByID(ctx, 1)
+		if !quaver.IsNotFound(err) {
+			t.Errorf("error = %v, want a not found error", err)
+		}
+	}
+	if got := n.Load(); got != 2 {
+		t.Errorf("sent %d requests, want 2", got)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	client, mux := setup(t,
+		quaver.WithCache(quaver.NewMemoryCache(100)),
+		// Later rules win ties, so this overrides the default for the pattern.
+		quaver.WithCacheTTL("user/*/scores/*/recent", 0),
+		quaver.WithCacheTTL("server/stats", time.Millisecond),
+	)
+	users := serve(mux, "GET /v2/user/1", http.StatusOK, userBody)
+	scores := serve(mux, "GET /v2/user/1/scores/1/recent", http.StatusOK, `{"scores": []}`)
+	stats := serve(mux, "GET /v2/server/stats", http.StatusOK, `{"online_users": 1}`)
+
+	for range 2 {
+		if _, _, err := client.Users.ListRecentScores(ctx, 1, quaver.GameMode4K, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := client.Users.GetByID(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := client.ServerStats.Get(ctx); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// The rule disables caching of recent scores only.
+	if got := scores.Load(); got != 2 {
+		t.Errorf("sent %d score requests, want 2", got)
+	}
+	if got := users.Load(); got != 1 {
+		t.Errorf("sent %d user requests, want 1", got)
+	}
+	// The server stats expired before the second request.
+	if got := stats.Load(); got != 2 {
+		t.Errorf("sent %d server stats requests, want 2", got)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	c := quaver.NewMemoryCache(2)
+
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+	c.Get("a")
+	c.Set("c", []byte("3"), 0)
+
+	// b was the least recently used.
+	if _, ok := c.Get("b"); ok {
+		t.Error("b was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%v was evicted", key)
+		}
+	}
+
+	c.Set("a", []byte("1"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("expired entry was returned")
+	}
+
+	c.Delete("c")
+	if _, ok := c.Get("c"); ok {
+		t.Error("deleted entry was returned")
+	}
+}
@@ -59,25 +59,25 @@ const (
 	ClanActivityOwnershipTransferred
 )
 
-func (s *ClansService) Get(ctx context.Context, id int) (*Clan, error) {
+func (s *ClansService) Get(ctx context.Context, id int) (*Clan, *Response, error) {
 	url := fmt.Sprintf("clan/%v", id)
 
 	var r struct {
 		Clan *Clan `json:"clan"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Clan, nil
+	return r.Clan, resp, nil
 }
 
-func (s *ClansService) ListActivity(ctx context.Context, clanID int, opts *ListOptions) ([]*ClanActivity, error) {
+func (s *ClansService) ListActivity(ctx context.Context, clanID int, opts *ListOptions) ([]*ClanActivity, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("clan/%v/activity?%v", clanID, v.Encode())
@@ -86,25 +86,34 @@ func (s *ClansService) ListActivity(ctx context.Context, clanID int, opts *ListO
 		Activities []*ClanActivity `json:"activity"`
 	}
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Activities, nil
+	resp.setPage(opts, len(r.Activities), 0)
+
+	return r.Activities, resp, nil
+}
+
+// ListActivityPager returns a pager over all of a clan's activity.
+func (s *ClansService) ListActivityPager(clanID int, opts *ListOptions) *Pager[*ClanActivity] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*ClanActivity, *Response, error) {
+		return s.ListActivity(ctx, clanID, &ListOptions{Page: page})
+	})
 }
 
-func (s *ClansService) ListMembers(ctx context.Context, clanID int) ([]*User, error) {
+func (s *ClansService) ListMembers(ctx context.Context, clanID int) ([]*User, *Response, error) {
 	url := fmt.Sprintf("clan/%v/members", clanID)
 
 	var r struct {
 		Members []*User `json:"members"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Members, nil
+	return r.Members, resp, nil
 }
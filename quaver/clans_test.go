@@ -0,0 +1,55 @@
+package quaver_test
+
+import (
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestClansGet(t *testing.T) {
+	client, _ := seededClient(t)
+
+	clan, _, err := client.Clans.Get(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clan.Name != "Example Clan" || clan.Tag != "EX" || clan.OwnerID != 1 {
+		t.Errorf("Get = %+v, want Example Clan owned by user 1", clan)
+	}
+
+	members, _, err := client.Clans.ListMembers(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := userIDs(members); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("ListMembers = %v, want [1 3]", got)
+	}
+
+	_, _, err = client.Clans.Get(ctx, 404)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("Get of a missing clan = %v, want a not found error", err)
+	}
+}
+
+func TestClansListActivity(t *testing.T) {
+	client, _ := seededClient(t)
+
+	activity, resp, err := client.Clans.ListActivity(ctx, 7, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 50 || activity[0].Message != "joined" {
+		t.Errorf("ListActivity returned %d items, want 50", len(activity))
+	}
+	if resp.NextPage != 1 {
+		t.Errorf("NextPage = %d, want 1", resp.NextPage)
+	}
+
+	activity, err = client.Clans.ListActivityPager(7, nil).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 55 || activity[54].Id != 55 {
+		t.Errorf("ListActivityPager returned %d items, want 55", len(activity))
+	}
+}
@@ -1,37 +1,142 @@
 package quaver
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
 	"io"
-	"net/http"
+	"strings"
+	"time"
 )
 
 type DownloadService service
 
-func (s *DownloadService) download(dst io.Writer, fileType string, itemID int) error {
+// WithDownloadCache caches downloaded map and replay files in cache instead of
+// the cache set with WithCache. Replays and maps downloaded by MD5 never
+// change, so they are stored without expiry.
+func WithDownloadCache(cache Cache) Option {
+	return func(c *Client) error {
+		c.downloadCache = cache
+		return nil
+	}
+}
+
+func (c *Client) fileCache() Cache {
+	if c.downloadCache != nil {
+		return c.downloadCache
+	}
+	return c.cache
+}
+
+// download writes a file to dst. If key is not empty, the file is cached
+// under it for ttl once verify, if set, accepts its contents.
+func (s *DownloadService) download(ctx context.Context, dst io.Writer, fileType string, itemID int, key string, ttl time.Duration, verify func([]byte) bool) (*Response, error) {
 	url := fmt.Sprintf("%vdownload/%v/%v", s.client.BaseURL, fileType, itemID)
 
-	resp, err := http.Get(url)
+	req, err := s.client.newRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := s.client.fileCache()
+	if key == "" {
+		cache = nil
+	}
+
+	if cache != nil && !isCacheBypassed(ctx) {
+		if b, ok := cache.Get(key); ok {
+			_, err = dst.Write(b)
+			return newCachedResponse(req), err
+		}
+	}
+
+	start := time.Now()
+	r, err := s.client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	resp := newResponse(r, time.Since(start))
+
+	err = checkResponse(r)
+	if err != nil {
+		return resp, err
+	}
+
+	if cache == nil {
+		_, err = io.Copy(dst, r.Body)
+		return resp, err
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(io.MultiWriter(dst, &buf), r.Body)
 	if err != nil {
-		return err
+		return resp, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("unable to download %v - not found", fileType)
+	if verify == nil || verify(buf.Bytes()) {
+		cache.Set(key, buf.Bytes(), ttl)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unable to download %v - HTTP%v", fileType, resp.StatusCode)
+	return resp, nil
+}
+
+// Map downloads the .qua file of a map.
+func (s *DownloadService) Map(dst io.Writer, mapID int) (*Response, error) {
+	return s.MapContext(context.Background(), dst, mapID)
+}
+
+// MapContext downloads the .qua file of a map, stopping when ctx is done.
+func (s *DownloadService) MapContext(ctx context.Context, dst io.Writer, mapID int) (*Response, error) {
+	var key string
+	ttl := s.client.cacheTTL("download/map/")
+	if ttl > 0 {
+		key = fmt.Sprintf("%vdownload/map/%v", s.client.BaseURL, mapID)
 	}
-	_, err = io.Copy(dst, resp.Body)
-	return err
+
+	return s.download(ctx, dst, "map", mapID, key, ttl, nil)
+}
+
+// MapByMD5Context downloads the .qua file of the map with the given MD5 hash.
+// As the file is addressed by its content, it is cached without expiry once
+// its hash has been verified.
+func (s *DownloadService) MapByMD5Context(ctx context.Context, dst io.Writer, md5Hash string) (*Response, error) {
+	key := "quaver:map:" + strings.ToLower(md5Hash)
+
+	if cache := s.client.fileCache(); cache != nil && !isCacheBypassed(ctx) {
+		if b, ok := cache.Get(key); ok {
+			_, err := dst.Write(b)
+			return newCachedResponse(nil), err
+		}
+	}
+
+	m, resp, err := s.client.Maps.GetByMD5(ctx, md5Hash)
+	if err != nil {
+		return resp, err
+	}
+	if m == nil {
+		return resp, fmt.Errorf("%w: map %v", ErrNotFound, md5Hash)
+	}
+
+	verify := func(b []byte) bool {
+		sum := md5.Sum(b)
+		return strings.EqualFold(hex.EncodeToString(sum[:]), md5Hash)
+	}
+
+	return s.download(ctx, dst, "map", m.ID, key, 0, verify)
 }
 
-func (s *DownloadService) Map(dst io.Writer, mapID int) error {
-	return s.download(dst, "map", mapID)
+// Replay downloads the .qr file of a replay.
+func (s *DownloadService) Replay(dst io.Writer, replayID int) (*Response, error) {
+	return s.ReplayContext(context.Background(), dst, replayID)
 }
 
-func (s *DownloadService) Replay(dst io.Writer, replayID int) error {
-	return s.download(dst, "replay", replayID)
+// ReplayContext downloads the .qr file of a replay, stopping when ctx is done.
+// Replays never change, so they are cached without expiry.
+func (s *DownloadService) ReplayContext(ctx context.Context, dst io.Writer, replayID int) (*Response, error) {
+	key := fmt.Sprintf("quaver:replay:%d", replayID)
+	return s.download(ctx, dst, "replay", replayID, key, 0, nil)
 }
@@ -0,0 +1,193 @@
+package quaver_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestDownloadMap(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("GET /v2/download/map/100", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("AudioFile: audio.mp3\n"))
+	})
+
+	var buf bytes.Buffer
+	_, err := client.Download.Map(&buf, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "AudioFile: audio.mp3\n" {
+		t.Errorf("Map = %q", buf.String())
+	}
+
+	buf.Reset()
+	_, err = client.Download.MapContext(ctx, &buf, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "AudioFile: audio.mp3\n" {
+		t.Errorf("MapContext = %q", buf.String())
+	}
+
+	_, err = client.Download.MapContext(ctx, &buf, 404)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("MapContext of a missing map = %v, want a not found error", err)
+	}
+}
+
+func TestDownloadReplay(t *testing.T) {
+	client, mux := setup(t)
+	want := "replay\x00\xff"
+	mux.HandleFunc("GET /v2/download/replay/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(want))
+	})
+
+	var buf bytes.Buffer
+	_, err := client.Download.Replay(&buf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("Replay = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	_, err = client.Download.ReplayContext(ctx, &buf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("ReplayContext = %q, want %q", buf.String(), want)
+	}
+}
+
+// headerTransport adds a header to every request, marking that it was used.
+type headerTransport struct {
+	base http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Transport", "custom")
+	return t.base.RoundTrip(req)
+}
+
+func TestDownloadUsesClient(t *testing.T) {
+	client, mux := setup(t,
+		quaver.WithHTTPClient(&http.Client{Transport: headerTransport{http.DefaultTransport}}),
+		quaver.WithHeader("X-Test", "1"),
+		quaver.WithUserAgent("downloader"),
+	)
+	mux.HandleFunc("GET /v2/download/map/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transport") != "custom" || r.Header.Get("X-Test") != "1" || r.UserAgent() != "downloader" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	var buf bytes.Buffer
+	_, err := client.Download.Map(&buf, 1)
+	if err != nil {
+		t.Fatalf("download did not go through the configured client: %v", err)
+	}
+}
+
+func TestDownloadContext(t *testing.T) {
+	client, mux := setup(t)
+	started := make(chan struct{})
+	mux.HandleFunc("GET /v2/download/map/1", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	// Cancelling a stuck download returns instead of waiting for the server.
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	var buf bytes.Buffer
+	_, err := client.Download.MapContext(ctx, &buf, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestDownloadMapByMD5(t *testing.T) {
+	file := "AudioFile: audio.mp3\n"
+	sum := md5.Sum([]byte(file))
+	hash := hex.EncodeToString(sum[:])
+
+	dir := t.TempDir()
+	var downloads int
+	for range 2 {
+		// Each client stands for a separate run sharing the cache directory.
+		cache, err := quaver.NewFileCache(dir, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, mux := setup(t, quaver.WithDownloadCache(cache))
+		serve(mux, "GET /v2/map/"+hash, http.StatusOK, `{"map": {"id": 100}}`)
+		n := serve(mux, "GET /v2/download/map/100", http.StatusOK, file)
+
+		var buf bytes.Buffer
+		_, err = client.Download.MapByMD5Context(ctx, &buf, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != file {
+			t.Errorf("MapByMD5Context = %q, want %q", buf.String(), file)
+		}
+		downloads += int(n.Load())
+	}
+	if downloads != 1 {
+		t.Errorf("downloaded the map %d times, want 1", downloads)
+	}
+}
+
+func TestDownloadMapByMD5Mismatch(t *testing.T) {
+	cache := quaver.NewMemoryCache(10)
+	client, mux := setup(t, quaver.WithDownloadCache(cache))
+	hash := "0123456789abcdef0123456789abcdef"
+	serve(mux, "GET /v2/map/"+hash, http.StatusOK, `{"map": {"id": 100}}`)
+	n := serve(mux, "GET /v2/download/map/100", http.StatusOK, "a different file")
+
+	// A file that does not match its hash is returned but not cached.
+	for range 2 {
+		var buf bytes.Buffer
+		if _, err := client.Download.MapByMD5Context(ctx, &buf, hash); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := n.Load(); got != 2 {
+		t.Errorf("downloaded the map %d times, want 2", got)
+	}
+}
+
+func TestDownloadReplayCached(t *testing.T) {
+	client, mux := setup(t, quaver.WithDownloadCache(quaver.NewMemoryCache(10)))
+	n := serve(mux, "GET /v2/download/replay/1", http.StatusOK, "replay")
+
+	for range 2 {
+		var buf bytes.Buffer
+		resp, err := client.Download.ReplayContext(ctx, &buf, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != "replay" {
+			t.Errorf("ReplayContext = %q, want %q (cached %v)", buf.String(), "replay", resp.Cached)
+		}
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("downloaded the replay %d times, want 1", got)
+	}
+}
@@ -34,11 +34,13 @@ const (
 	GradeD  Grade = "D"
 )
 
+// Modifier is a set of gameplay modifiers, using the bit values the game
+// writes to replays and the API returns.
 type Modifier int64
 
 const (
-	ModifierNone Modifier = 1 << iota
-	ModifierNoSliderVelocity
+	ModifierNone             Modifier = 0
+	ModifierNoSliderVelocity Modifier = 1 << (iota - 1)
 	ModifierSpeed05X
 	ModifierSpeed06X
 	ModifierSpeed07X
@@ -0,0 +1,75 @@
+package quaver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// maxErrorBodySize is the amount of the response body kept on an APIError.
+const maxErrorBodySize = 1024
+
+var (
+	// ErrNotFound is matched by an APIError with a 404 status code.
+	ErrNotFound = errors.New("quaver: not found")
+
+	// ErrRateLimited is matched by an APIError with a 429 status code.
+	ErrRateLimited = errors.New("quaver: rate limited")
+
+	// ErrServer is matched by an APIError with a 5xx status code.
+	ErrServer = errors.New("quaver: server error")
+)
+
+// APIError is returned when the Quaver API responds with a non-200 status code.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Method and URL identify the request that failed.
+	Method string
+	URL    string
+
+	// Header holds the response headers.
+	Header http.Header
+
+	// Body is the start of the raw response body, truncated to 1KB.
+	Body []byte
+
+	// Payload is the decoded error returned by the API, if the body contained one.
+	Payload *Error
+}
+
+func (e *APIError) Error() string {
+	if e.Payload != nil && e.Payload.Error != "" {
+		return fmt.Sprintf("quaver: %v %v: %d %v", e.Method, e.URL, e.StatusCode, e.Payload.Error)
+	}
+	return fmt.Sprintf("quaver: %v %v: %d %v", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500 && e.StatusCode <= 599
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an APIError for a missing resource.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsRateLimited reports whether err is an APIError caused by rate limiting.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsServerError reports whether err is an APIError with a 5xx status code.
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServer)
+}
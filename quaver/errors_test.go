@@ -0,0 +1,117 @@
+package quaver_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestAPIError(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("GET /v2/map/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var status int
+		fmt.Sscan(r.PathValue("id"), &status)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"error": %q}`, http.StatusText(status))
+	})
+
+	tests := []struct {
+		status int
+		is     func(error) bool
+		target error
+	}{
+		{http.StatusNotFound, quaver.IsNotFound, quaver.ErrNotFound},
+		{http.StatusTooManyRequests, quaver.IsRateLimited, quaver.ErrRateLimited},
+		{http.StatusInternalServerError, quaver.IsServerError, quaver.ErrServer},
+		{http.StatusServiceUnavailable, quaver.IsServerError, quaver.ErrServer},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			m, _, err := client.Maps.GetByID(ctx, tt.status)
+			if m != nil {
+				t.Errorf("GetByID returned a map: %+v", m)
+			}
+
+			var apiErr *quaver.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want an *APIError", err)
+			}
+			wantURL := fmt.Sprintf("%vmap/%d", client.BaseURL, tt.status)
+			if apiErr.StatusCode != tt.status || apiErr.Method != http.MethodGet || apiErr.URL != wantURL {
+				t.Errorf("APIError = %d %v %v, want %d GET %v", apiErr.StatusCode, apiErr.Method, apiErr.URL, tt.status, wantURL)
+			}
+			if got := apiErr.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Header Content-Type = %q, want application/json", got)
+			}
+
+			message := http.StatusText(tt.status)
+			if apiErr.Payload == nil || apiErr.Payload.Error != message {
+				t.Errorf("Payload = %+v, want %q", apiErr.Payload, message)
+			}
+			if !strings.Contains(string(apiErr.Body), message) {
+				t.Errorf("Body = %q, want it to contain %q", apiErr.Body, message)
+			}
+			if !strings.Contains(err.Error(), message) {
+				t.Errorf("Error() = %q, want it to contain %q", err.Error(), message)
+			}
+
+			if !tt.is(err) || !errors.Is(err, tt.target) {
+				t.Errorf("error %v does not match %v", err, tt.target)
+			}
+			for _, other := range []error{quaver.ErrNotFound, quaver.ErrRateLimited, quaver.ErrServer} {
+				if other != tt.target && errors.Is(err, other) {
+					t.Errorf("error %v matches %v", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIErrorRawBody(t *testing.T) {
+	client, mux := setup(t)
+
+	// A maintenance page is not an API error payload, and is cut at 1KB.
+	page := "<html>" + strings.Repeat("Down for maintenance. ", 100) + "</html>"
+	mux.HandleFunc("GET /v2/server/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, page)
+	})
+
+	_, _, err := client.ServerStats.Get(ctx)
+	var apiErr *quaver.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want an *APIError", err)
+	}
+	if apiErr.Payload != nil {
+		t.Errorf("Payload = %+v, want nil", apiErr.Payload)
+	}
+	if len(apiErr.Body) != 1024 || !bytes.HasPrefix([]byte(page), apiErr.Body) {
+		t.Errorf("Body has %d bytes, want the first 1024 of the page", len(apiErr.Body))
+	}
+	if !strings.Contains(err.Error(), "502 Bad Gateway") {
+		t.Errorf("Error() = %q, want the status text", err.Error())
+	}
+}
+
+func TestAPIErrorDownload(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("GET /v2/download/map/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "Map not found"}`)
+	})
+
+	var buf bytes.Buffer
+	_, err := client.Download.Map(&buf, 1)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("error = %v, want a not found error", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q for a missing map", buf.String())
+	}
+}
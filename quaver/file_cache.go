@@ -0,0 +1,202 @@
+package quaver
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileCache is a Cache that stores entries as files in a directory, so they
+// persist across runs. Once the total size of the entries exceeds the limit,
+// the least recently used ones are removed.
+//
+// The total size is counted once and then tracked as entries are written and
+// deleted, so other processes sharing the directory are only accounted for
+// when the cache next evicts.
+type FileCache struct {
+	dir     string
+	maxSize int64
+
+	mu sync.Mutex
+	// size is the total size of the entries, valid once counted is set.
+	size    int64
+	counted bool
+}
+
+// NewFileCache returns a FileCache storing entries in dir, creating it if
+// needed. A non-positive maxSize disables eviction.
+func NewFileCache(dir string, maxSize int64) (*FileCache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &FileCache{dir: dir, maxSize: maxSize}, nil
+}
+
+// path returns the file an entry is stored in. Keys are hashed, so entries
+// keyed by content such as a map MD5 or replay ID are content-addressed.
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(f.dir, name[:2], name)
+}
+
+// fileCacheTempPrefix starts the names of entries still being written.
+const fileCacheTempPrefix = ".tmp-"
+
+// evictionTarget is the fraction of the size limit eviction shrinks the cache
+// to, so that eviction does not run again on every following write.
+const evictionTarget = 0.9
+
+// Each file starts with the entry's expiry time as Unix nanoseconds, zero if
+// it never expires, followed by the value.
+const fileCacheHeaderSize = 8
+
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	path := f.path(key)
+
+	b, err := os.ReadFile(path)
+	if err != nil || len(b) < fileCacheHeaderSize {
+		return nil, false
+	}
+
+	expires := int64(binary.BigEndian.Uint64(b))
+	if expires != 0 && time.Now().UnixNano() > expires {
+		f.Delete(key)
+		return nil, false
+	}
+
+	// The modification time tracks use for eviction.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return b[fileCacheHeaderSize:], true
+}
+
+func (f *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	var expires int64
+	if ttl > 0 {
+		expires = time.Now().Add(ttl).UnixNano()
+	}
+
+	b := make([]byte, fileCacheHeaderSize+len(value))
+	binary.BigEndian.PutUint64(b, uint64(expires))
+	copy(b[fileCacheHeaderSize:], value)
+
+	path := f.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so readers never see a partial entry.
+	tmp, err := os.CreateTemp(filepath.Dir(path), fileCacheTempPrefix+"*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	old := fileSize(path)
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	f.size += int64(len(b)) - old
+
+	if f.maxSize > 0 && (!f.counted || f.size > f.maxSize) {
+		f.evict()
+	}
+}
+
+func (f *FileCache) Delete(key string) {
+	path := f.path(key)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	size := fileSize(path)
+	if os.Remove(path) == nil {
+		f.size -= size
+	}
+}
+
+// fileSize returns the size of the file at path, or zero if it does not
+// exist.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// evict counts the size of the entries and, if it exceeds the size limit,
+// removes the least recently used ones until the cache is back under
+// evictionTarget of the limit. f.mu must be held.
+func (f *FileCache) evict() {
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var entries []entry
+	var total int64
+	err := filepath.WalkDir(f.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), fileCacheTempPrefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entries = append(entries, entry{path, info.Size(), info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	f.size, f.counted = total, true
+	if total <= f.maxSize {
+		return
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	target := int64(float64(f.maxSize) * evictionTarget)
+	for _, e := range entries {
+		if f.size <= target {
+			break
+		}
+		if os.Remove(e.path) == nil {
+			f.size -= e.size
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package quaver
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCache(t *testing.T) {
+	c, err := NewFileCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Set("a", []byte("value"), 0)
+	if b, ok := c.Get("a"); !ok || string(b) != "value" {
+		t.Errorf("Get = %q, %v, want %q", b, ok, "value")
+	}
+
+	c.Set("b", []byte("value"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := c.Get("b"); ok {
+		t.Error("expired entry was returned")
+	}
+	if _, err := os.Stat(c.path("b")); !os.IsNotExist(err) {
+		t.Errorf("expired entry was not removed: %v", err)
+	}
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("deleted entry was returned")
+	}
+}
+
+func TestFileCacheEvict(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewFileCache(dir, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A file still being written by another process is neither counted nor
+	// evicted.
+	tmp := filepath.Join(dir, fileCacheTempPrefix+"other")
+	if err := os.WriteFile(tmp, make([]byte, 5000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each entry takes 108 bytes, so the tenth goes over the limit.
+	value := bytes.Repeat([]byte("x"), 100)
+	start := time.Now().Add(-time.Hour)
+	for i := range 10 {
+		key := fmt.Sprint(i)
+		c.Set(key, value, 0)
+		used := start.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(c.path(key), used, used); err != nil {
+			t.Fatal(err)
+		}
+		if i < 9 && c.size != int64(108*(i+1)) {
+			t.Errorf("size after %d entries = %d, want %d", i+1, c.size, 108*(i+1))
+		}
+	}
+
+	// Eviction removes the least recently used entries down to 900 bytes.
+	for i := range 10 {
+		_, ok := c.Get(fmt.Sprint(i))
+		if want := i >= 2; ok != want {
+			t.Errorf("entry %d present = %v, want %v", i, ok, want)
+		}
+	}
+	if c.size != 8*108 {
+		t.Errorf("size = %d, want %d", c.size, 8*108)
+	}
+	if _, err := os.Stat(tmp); err != nil {
+		t.Errorf("temporary file was evicted: %v", err)
+	}
+
+	c.Delete("5")
+	if c.size != 7*108 {
+		t.Errorf("size after Delete = %d, want %d", c.size, 7*108)
+	}
+	c.Set("6", value[:50], 0)
+	if c.size != 6*108+58 {
+		t.Errorf("size after overwrite = %d, want %d", c.size, 6*108+58)
+	}
+}
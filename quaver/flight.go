@@ -0,0 +1,159 @@
+package quaver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup coalesces concurrent identical requests, so that callers asking
+// for the same URL at the same time share a single round trip.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done    chan struct{}
+	waiters int
+	ctx     *flightContext
+
+	resp *Response
+	body []byte
+	err  error
+}
+
+// do calls fn once for all concurrent callers with the same key and returns
+// its results to each of them. The shared call runs detached from the context
+// of any one caller, with the latest deadline among them. A caller whose ctx
+// is done stops waiting, and the call is cancelled once no callers are left
+// waiting for it.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (*Response, []byte, error)) (*Response, []byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	// A call past its deadline is about to fail, so later callers start a new
+	// one rather than sharing the error.
+	call, ok := g.calls[key]
+	if ok && call.ctx.Err() == nil {
+		call.waiters++
+		call.ctx.join(ctx)
+	} else {
+		call = &flightCall{
+			done:    make(chan struct{}),
+			waiters: 1,
+			ctx:     newFlightContext(ctx),
+		}
+		g.calls[key] = call
+
+		go func() {
+			call.resp, call.body, call.err = fn(call.ctx)
+			call.ctx.cancel(context.Canceled)
+			close(call.done)
+
+			g.mu.Lock()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		// Each caller gets its own copy of the response, as services fill in
+		// its pagination fields.
+		var resp *Response
+		if call.resp != nil {
+			r := *call.resp
+			resp = &r
+		}
+		return resp, call.body, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.ctx.cancel(context.Canceled)
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, nil, ctx.Err()
+	}
+}
+
+// flightContext is the context of a shared call. It keeps the values of the
+// caller that started the call, ignores the cancellation of every caller and
+// has the latest deadline among them, or none if any caller has none.
+type flightContext struct {
+	context.Context
+	cancelCause context.CancelCauseFunc
+
+	mu       sync.Mutex
+	deadline time.Time
+	bounded  bool
+	timer    *time.Timer
+}
+
+func newFlightContext(ctx context.Context) *flightContext {
+	c := &flightContext{}
+	c.Context, c.cancelCause = context.WithCancelCause(context.WithoutCancel(ctx))
+
+	deadline, ok := ctx.Deadline()
+	if ok {
+		c.deadline, c.bounded = deadline, true
+		c.timer = time.AfterFunc(time.Until(deadline), func() {
+			c.cancelCause(context.DeadlineExceeded)
+		})
+	}
+	return c
+}
+
+// join extends the deadline of c to that of ctx, if it is later.
+func (c *flightContext) join(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.bounded {
+		return
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		c.bounded = false
+		c.timer.Stop()
+		return
+	}
+	if deadline.After(c.deadline) {
+		c.deadline = deadline
+		c.timer.Reset(time.Until(deadline))
+	}
+}
+
+func (c *flightContext) cancel(cause error) {
+	c.mu.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.mu.Unlock()
+	c.cancelCause(cause)
+}
+
+func (c *flightContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deadline, c.bounded
+}
+
+// Err reports context.DeadlineExceeded once the deadline has passed, like
+// the contexts returned by context.WithDeadline.
+func (c *flightContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
@@ -0,0 +1,267 @@
+package quaver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitWaiters blocks until the call for key in g has n callers waiting on it.
+func waitWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		g.mu.Lock()
+		var waiters int
+		if call := g.calls[key]; call != nil {
+			waiters = call.waiters
+		}
+		g.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d callers waiting on %q, want %d", waiters, key, n)
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestFlightGroup(t *testing.T) {
+	const callers = 5
+
+	var g flightGroup
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context) (*Response, []byte, error) {
+		calls.Add(1)
+		<-release
+		return &Response{}, []byte("body"), nil
+	}
+
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, body, err := g.do(context.Background(), "key", fn)
+			if err != nil || string(body) != "body" || resp == nil {
+				t.Errorf("do = %v, %q, %v", resp, body, err)
+			}
+		}()
+	}
+	waitWaiters(t, &g, "key", callers)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+
+	// The call is removed from the group just after it completes.
+	waitWaiters(t, &g, "key", 0)
+}
+
+func TestFlightGroupDeadline(t *testing.T) {
+	var g flightGroup
+	started := make(chan struct{})
+	release := make(chan struct{})
+	result := make(chan error, 1)
+
+	fn := func(ctx context.Context) (*Response, []byte, error) {
+		close(started)
+		<-release
+		return nil, nil, ctx.Err()
+	}
+
+	short, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	go func() {
+		_, _, err := g.do(short, "key", fn)
+		result <- err
+	}()
+	<-started
+
+	// A caller without a deadline lifts the deadline of the shared call.
+	long := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(context.Background(), "key", fn)
+		long <- err
+	}()
+	waitWaiters(t, &g, "key", 2)
+
+	// The shared call outlives the deadline of the caller that started it.
+	if err := <-result; err != context.DeadlineExceeded {
+		t.Errorf("short caller error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	close(release)
+	if err := <-long; err != nil {
+		t.Errorf("shared call error = %v, want nil", err)
+	}
+}
+
+func TestFlightGroupCancel(t *testing.T) {
+	var g flightGroup
+	started := make(chan struct{})
+	cause := make(chan error, 1)
+
+	fn := func(ctx context.Context) (*Response, []byte, error) {
+		close(started)
+		<-ctx.Done()
+		cause <- ctx.Err()
+		return nil, nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(ctx, "key", fn)
+		result <- err
+	}()
+	<-started
+	cancel()
+
+	// The only caller leaving cancels the shared call.
+	if err := <-result; err != context.Canceled {
+		t.Errorf("do error = %v, want %v", err, context.Canceled)
+	}
+	if err := <-cause; err != context.Canceled {
+		t.Errorf("shared call error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// flightClient returns a client for a test server running handler.
+func flightClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	client, err := NewClient(WithBaseURL(srv.URL+"/v2/"), WithRetry(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestSingleFlight(t *testing.T) {
+	const callers = 10
+
+	var n atomic.Int32
+	release := make(chan struct{})
+	client := flightClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"online_users": 1}`))
+	})
+	key := "GET " + client.BaseURL.String() + "server/stats"
+
+	var wg sync.WaitGroup
+	stats := make([]*ServerStats, callers)
+	errs := make([]error, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats[i], _, errs[i] = client.ServerStats.Get(context.Background())
+		}()
+	}
+
+	// Let every caller join before the response arrives.
+	waitWaiters(t, &client.flight, key, callers)
+	close(release)
+	wg.Wait()
+
+	if got := n.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+	for i := range callers {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if stats[i].OnlineUsers != 1 {
+			t.Errorf("caller %d got %+v", i, stats[i])
+		}
+		for j := range i {
+			if stats[i] == stats[j] {
+				t.Errorf("callers %d and %d share a result", i, j)
+			}
+		}
+	}
+}
+
+func TestSingleFlightCancel(t *testing.T) {
+	release := make(chan struct{})
+	client := flightClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+			_, _ = w.Write([]byte(`{"online_users": 1}`))
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+	key := "GET " + client.BaseURL.String() + "server/stats"
+
+	short, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := client.ServerStats.Get(short)
+		done <- err
+	}()
+	waitWaiters(t, &client.flight, key, 1)
+
+	result := make(chan error, 1)
+	go func() {
+		_, _, err := client.ServerStats.Get(context.Background())
+		result <- err
+	}()
+	waitWaiters(t, &client.flight, key, 2)
+
+	// The first caller giving up does not fail the one still waiting.
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("short caller error = %v, want %v", err, context.Canceled)
+	}
+	release <- struct{}{}
+	if err := <-result; err != nil {
+		t.Errorf("waiting caller error = %v", err)
+	}
+}
+
+func TestFlightGroupExpired(t *testing.T) {
+	var g flightGroup
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context) (*Response, []byte, error) {
+		calls.Add(1)
+		<-release
+		return nil, nil, ctx.Err()
+	}
+
+	// The first call is past its deadline but its fn has not returned, so a
+	// new caller starts a call of its own.
+	short, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	first := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(short, "key", fn)
+		first <- err
+	}()
+	<-first
+
+	second := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(context.Background(), "key", fn)
+		second <- err
+	}()
+	waitWaiters(t, &g, "key", 1)
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("second caller error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,140 @@
+package quaver
+
+import "errors"
+
+// ErrJudgementWindows is returned by RatingsAccuracy for plays whose
+// judgement windows were changed by Strict or Chill. Their ratings accuracy
+// can only be found by judging the replay again with the standard windows.
+var ErrJudgementWindows = errors.New("quaver: ratings accuracy requires the replay of plays with Strict or Chill")
+
+// Judgement weights used for accuracy.
+const (
+	weightMarvelous = 100
+	weightPerfect   = 98.25
+	weightGreat     = 65
+	weightGood      = 25
+	weightOkay      = -100
+	weightMiss      = -50
+)
+
+// Judgements are the judgement counts of a play.
+type Judgements struct {
+	Marvelous int
+	Perfect   int
+	Great     int
+	Good      int
+	Okay      int
+	Miss      int
+}
+
+// Total returns the total number of judgements.
+func (j Judgements) Total() int {
+	return j.Marvelous + j.Perfect + j.Great + j.Good + j.Okay + j.Miss
+}
+
+// Accuracy returns the weighted accuracy of the judgements, from 0 to 100.
+func (j Judgements) Accuracy() float64 {
+	total := j.Total()
+	if total == 0 {
+		return 0
+	}
+
+	weighted := float64(j.Marvelous)*weightMarvelous +
+		float64(j.Perfect)*weightPerfect +
+		float64(j.Great)*weightGreat +
+		float64(j.Good)*weightGood +
+		float64(j.Okay)*weightOkay +
+		float64(j.Miss)*weightMiss
+
+	return max(weighted/(float64(total)*weightMarvelous), 0) * 100
+}
+
+// RatingsAccuracy returns the accuracy used for the performance rating of a
+// play with the given modifiers. It equals Accuracy unless the play used
+// Strict or Chill, in which case ErrJudgementWindows is returned.
+func (j Judgements) RatingsAccuracy(mods Modifier) (float64, error) {
+	if mods&(ModifierStrict|ModifierChill) != 0 {
+		return 0, ErrJudgementWindows
+	}
+	return j.Accuracy(), nil
+}
+
+// Grade returns the grade given for the accuracy of the judgements.
+func (j Judgements) Grade() Grade {
+	return AccuracyGrade(j.Accuracy())
+}
+
+// AccuracyGrade returns the grade given for an accuracy from 0 to 100.
+func AccuracyGrade(accuracy float64) Grade {
+	switch {
+	case accuracy >= 100:
+		return GradeX
+	case accuracy >= 99:
+		return GradeSS
+	case accuracy >= 95:
+		return GradeS
+	case accuracy >= 90:
+		return GradeA
+	case accuracy >= 80:
+		return GradeB
+	case accuracy >= 70:
+		return GradeC
+	}
+	return GradeD
+}
+
+// Judgements returns the judgement counts of the score.
+func (s *Score) Judgements() Judgements {
+	return Judgements{
+		Marvelous: s.CountMarvelous,
+		Perfect:   s.CountPerfect,
+		Great:     s.CountGreat,
+		Good:      s.CountGood,
+		Okay:      s.CountOkay,
+		Miss:      s.CountMiss,
+	}
+}
+
+// RatingsAccuracy returns the accuracy used for the performance rating of the
+// score. See Judgements.RatingsAccuracy.
+func (s *Score) RatingsAccuracy() (float64, error) {
+	return s.Judgements().RatingsAccuracy(Modifier(s.Modifiers))
+}
+
+// Judgements returns the judgement counts of the score.
+func (s *MultiplayerMatchScore) Judgements() Judgements {
+	return Judgements{
+		Marvelous: s.CountMarvelous,
+		Perfect:   s.CountPerfect,
+		Great:     s.CountGreat,
+		Good:      s.CountGood,
+		Okay:      s.CountOkay,
+		Miss:      s.CountMiss,
+	}
+}
+
+// RatingsAccuracy returns the accuracy used for the performance rating of the
+// score. See Judgements.RatingsAccuracy.
+func (s *MultiplayerMatchScore) RatingsAccuracy() (float64, error) {
+	return s.Judgements().RatingsAccuracy(Modifier(s.Modifiers))
+}
+
+// Grade returns the grade of the score, which the API does not return for
+// multiplayer scores.
+func (s *MultiplayerMatchScore) Grade() Grade {
+	return AccuracyGrade(s.Accuracy)
+}
+
+// Judgements returns the total judgement counts of the user. Their accuracy
+// weighs every judgement equally, so it differs from OverallAccuracy, which
+// is calculated from the user's best scores.
+func (s *Statistics) Judgements() Judgements {
+	return Judgements{
+		Marvelous: s.TotalMarvelous,
+		Perfect:   s.TotalPerfect,
+		Great:     s.TotalGreat,
+		Good:      s.TotalGood,
+		Okay:      s.TotalOkay,
+		Miss:      s.TotalMiss,
+	}
+}
@@ -0,0 +1,108 @@
+package quaver_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestJudgementsAccuracy(t *testing.T) {
+	tests := []struct {
+		name string
+		j    quaver.Judgements
+		want float64
+	}{
+		{"none", quaver.Judgements{}, 0},
+		{"all marvelous", quaver.Judgements{Marvelous: 500}, 100},
+		{"all perfect", quaver.Judgements{Perfect: 500}, 98.25},
+		{"all great", quaver.Judgements{Great: 500}, 65},
+		{"all good", quaver.Judgements{Good: 500}, 25},
+		// Okays and misses weigh less than nothing, so accuracy stops at 0.
+		{"all okay", quaver.Judgements{Okay: 500}, 0},
+		{"all miss", quaver.Judgements{Miss: 500}, 0},
+		{"marvelous and perfect", quaver.Judgements{Marvelous: 3, Perfect: 1}, (300 + 98.25) / 4},
+		{"marvelous and miss", quaver.Judgements{Marvelous: 2, Miss: 1}, 50},
+		{"marvelous and okay", quaver.Judgements{Marvelous: 1, Okay: 1}, 0},
+		{"mixed", quaver.Judgements{Marvelous: 6, Perfect: 4, Great: 2, Good: 2, Okay: 1, Miss: 1},
+			(600 + 4*98.25 + 2*65 + 2*25 - 100 - 50) / 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.j.Accuracy(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Accuracy = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccuracyGrade(t *testing.T) {
+	tests := []struct {
+		accuracy float64
+		want     quaver.Grade
+	}{
+		{100, quaver.GradeX},
+		{99.99, quaver.GradeSS},
+		{99, quaver.GradeSS},
+		{98.99, quaver.GradeS},
+		{95, quaver.GradeS},
+		{94.99, quaver.GradeA},
+		{90, quaver.GradeA},
+		{89.99, quaver.GradeB},
+		{80, quaver.GradeB},
+		{79.99, quaver.GradeC},
+		{70, quaver.GradeC},
+		{69.99, quaver.GradeD},
+		{0, quaver.GradeD},
+	}
+	for _, tt := range tests {
+		if got := quaver.AccuracyGrade(tt.accuracy); got != tt.want {
+			t.Errorf("AccuracyGrade(%v) = %v, want %v", tt.accuracy, got, tt.want)
+		}
+	}
+
+	if g := (quaver.Judgements{Marvelous: 1000}).Grade(); g != quaver.GradeX {
+		t.Errorf("all marvelous graded %v, want X", g)
+	}
+	if g := (quaver.Judgements{Marvelous: 999, Perfect: 1}).Grade(); g != quaver.GradeSS {
+		t.Errorf("one perfect graded %v, want SS", g)
+	}
+	if g := (quaver.Judgements{Okay: 1000}).Grade(); g != quaver.GradeD {
+		t.Errorf("all okay graded %v, want D", g)
+	}
+}
+
+func TestRatingsAccuracy(t *testing.T) {
+	j := quaver.Judgements{Marvelous: 90, Perfect: 10}
+	tests := []struct {
+		mods    quaver.Modifier
+		wantErr bool
+	}{
+		{0, false},
+		{quaver.ModifierMirror | quaver.ModifierSpeed12X, false},
+		{quaver.ModifierStrict, true},
+		{quaver.ModifierChill, true},
+		{quaver.ModifierStrict | quaver.ModifierMirror, true},
+	}
+	for _, tt := range tests {
+		got, err := j.RatingsAccuracy(tt.mods)
+		if tt.wantErr {
+			if !errors.Is(err, quaver.ErrJudgementWindows) {
+				t.Errorf("RatingsAccuracy(%v) = %v, %v, want ErrJudgementWindows", tt.mods, got, err)
+			}
+			continue
+		}
+		if err != nil || got != j.Accuracy() {
+			t.Errorf("RatingsAccuracy(%v) = %v, %v, want %v", tt.mods, got, err, j.Accuracy())
+		}
+	}
+
+	s := &quaver.Score{CountMarvelous: 90, CountPerfect: 10, Modifiers: int(quaver.ModifierChill)}
+	if s.Judgements() != j {
+		t.Errorf("Score.Judgements = %+v, want %+v", s.Judgements(), j)
+	}
+	if _, err := s.RatingsAccuracy(); !errors.Is(err, quaver.ErrJudgementWindows) {
+		t.Errorf("Score.RatingsAccuracy with Chill = %v, want ErrJudgementWindows", err)
+	}
+}
@@ -13,59 +13,106 @@ type Leaderboard struct {
 	Users      []User `json:"users"`
 }
 
+func (l *Leaderboard) users() []*User {
+	users := make([]*User, len(l.Users))
+	for i := range l.Users {
+		users[i] = &l.Users[i]
+	}
+	return users
+}
+
 // Global returns the global leaderboard for the specified mode.
-func (s *LeaderboardsService) Global(ctx context.Context, mode GameMode, opts *ListOptions) (*Leaderboard, error) {
+func (s *LeaderboardsService) Global(ctx context.Context, mode GameMode, opts *ListOptions) (*Leaderboard, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("leaderboard/global?mode=%d&%v", mode, v.Encode())
 
 	var r Leaderboard
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return &r, nil
+	resp.setPage(opts, len(r.Users), leaderboardPageSize)
+
+	return &r, resp, nil
+}
+
+// GlobalPager returns a pager over all users on the global leaderboard for the specified mode.
+func (s *LeaderboardsService) GlobalPager(mode GameMode, opts *ListOptions) *Pager[*User] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*User, *Response, error) {
+		lb, resp, err := s.Global(ctx, mode, &ListOptions{Page: page})
+		if err != nil {
+			return nil, resp, err
+		}
+		return lb.users(), resp, nil
+	})
 }
 
 // Country returns the leaderboard for the specified country and mode.
-func (s *LeaderboardsService) Country(ctx context.Context, country string, mode GameMode, opts *ListOptions) (*Leaderboard, error) {
+func (s *LeaderboardsService) Country(ctx context.Context, country string, mode GameMode, opts *ListOptions) (*Leaderboard, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("leaderboard/country?country=%v&mode=%d&%v", country, mode, v.Encode())
 
 	var r Leaderboard
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return &r, nil
+	resp.setPage(opts, len(r.Users), leaderboardPageSize)
+
+	return &r, resp, nil
+}
+
+// CountryPager returns a pager over all users on the leaderboard for the specified country and mode.
+func (s *LeaderboardsService) CountryPager(country string, mode GameMode, opts *ListOptions) *Pager[*User] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*User, *Response, error) {
+		lb, resp, err := s.Country(ctx, country, mode, &ListOptions{Page: page})
+		if err != nil {
+			return nil, resp, err
+		}
+		return lb.users(), resp, nil
+	})
 }
 
 // Hits returns the total hits leaderboard.
-func (s *LeaderboardsService) Hits(ctx context.Context, opts *ListOptions) (*Leaderboard, error) {
+func (s *LeaderboardsService) Hits(ctx context.Context, opts *ListOptions) (*Leaderboard, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("leaderboard/hits?%v", v.Encode())
 
 	var r Leaderboard
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return &r, nil
+	resp.setPage(opts, len(r.Users), leaderboardPageSize)
+
+	return &r, resp, nil
+}
+
+// HitsPager returns a pager over all users on the total hits leaderboard.
+func (s *LeaderboardsService) HitsPager(opts *ListOptions) *Pager[*User] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*User, *Response, error) {
+		lb, resp, err := s.Hits(ctx, &ListOptions{Page: page})
+		if err != nil {
+			return nil, resp, err
+		}
+		return lb.users(), resp, nil
+	})
 }
@@ -0,0 +1,75 @@
+package quaver_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func userIDs(users []*quaver.User) []int {
+	var ids []int
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func TestLeaderboards(t *testing.T) {
+	client, _ := seededClient(t)
+
+	tests := []struct {
+		name  string
+		get   func() (*quaver.Leaderboard, *quaver.Response, error)
+		pager *quaver.Pager[*quaver.User]
+		want  []int
+	}{
+		{
+			"global",
+			func() (*quaver.Leaderboard, *quaver.Response, error) {
+				return client.Leaderboards.Global(ctx, quaver.GameMode7K, nil)
+			},
+			client.Leaderboards.GlobalPager(quaver.GameMode7K, nil),
+			[]int{3, 2, 1},
+		},
+		{
+			"country",
+			func() (*quaver.Leaderboard, *quaver.Response, error) {
+				return client.Leaderboards.Country(ctx, "US", quaver.GameMode4K, nil)
+			},
+			client.Leaderboards.CountryPager("US", quaver.GameMode4K, nil),
+			[]int{1, 3},
+		},
+		{
+			"hits",
+			func() (*quaver.Leaderboard, *quaver.Response, error) {
+				return client.Leaderboards.Hits(ctx, nil)
+			},
+			client.Leaderboards.HitsPager(nil),
+			[]int{3, 2, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb, _, err := tt.get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, u := range lb.Users {
+				got = append(got, u.ID)
+			}
+			if !slices.Equal(got, tt.want) || lb.TotalUsers != len(tt.want) {
+				t.Errorf("leaderboard = %v of %d, want %v", got, lb.TotalUsers, tt.want)
+			}
+
+			users, err := tt.pager.All(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := userIDs(users); !slices.Equal(got, tt.want) {
+				t.Errorf("pager = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package quaver_test
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"net/http"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+	"github.com/maskeddd/go-quaver/quavertest"
+)
+
+var record = flag.Bool("record", false, "record the fixtures in testdata/api from the live Quaver API")
+
+// liveDir holds responses recorded from the live API, with the personal
+// fields of users scrubbed.
+const liveDir = "testdata/api"
+
+// liveUserID is the user the live fixtures are recorded for.
+const liveUserID = 1
+
+// liveClient returns a client replaying the responses in liveDir. With
+// -record, it sends its requests to the live API and records the responses
+// again, at a rate the API is comfortable with.
+func liveClient(t *testing.T) *quaver.Client {
+	t.Helper()
+
+	rec := quavertest.NewRecorder(liveDir, quavertest.ModeReplay)
+	opts := []quaver.Option{
+		quaver.WithHTTPClient(&http.Client{Transport: rec}),
+		quaver.WithRetry(nil),
+	}
+	if *record {
+		rec.Mode = quavertest.ModeRecord
+		opts = append(opts, quaver.WithRateLimit(2, 1))
+	}
+
+	c, err := quaver.NewClient(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// checkLive fails t on err, skipping instead if the response was never
+// recorded.
+func checkLive(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("%v; record the fixtures with go test -run Live -record", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLiveUser(t *testing.T) {
+	client := liveClient(t)
+
+	user, _, err := client.Users.GetByID(ctx, liveUserID)
+	checkLive(t, err)
+	if user.ID != liveUserID || user.Username == "" || user.TimeRegistered.IsZero() {
+		t.Errorf("GetByID = %+v, want user %d with a name and registration time", user.UserCompact, liveUserID)
+	}
+	if user.SteamID != "" || (user.DiscordID != nil && *user.DiscordID != "") {
+		t.Errorf("personal fields were recorded: steam_id %q, discord_id %v", user.SteamID, user.DiscordID)
+	}
+
+	scores, _, err := client.Users.ListBestScores(ctx, liveUserID, quaver.GameMode4K, nil)
+	checkLive(t, err)
+	for _, s := range scores {
+		if s.Map.MD5 == "" || s.PerformanceRating <= 0 {
+			t.Errorf("best score %d has map %q and rating %v", s.ID, s.Map.MD5, s.PerformanceRating)
+		}
+	}
+}
+
+func TestLiveMap(t *testing.T) {
+	client := liveClient(t)
+
+	// The map comes from the recorded best scores, so that it is ranked.
+	scores, _, err := client.Users.ListBestScores(ctx, liveUserID, quaver.GameMode4K, nil)
+	checkLive(t, err)
+	if len(scores) == 0 {
+		t.Skip("the user has no best scores")
+	}
+	md5 := scores[0].Map.MD5
+
+	m, _, err := client.Maps.GetByMD5(ctx, md5)
+	checkLive(t, err)
+	if m.MD5 != md5 || m.ID == 0 || m.MapsetID == 0 {
+		t.Errorf("GetByMD5 = %+v, want the map with hash %v", m, md5)
+	}
+
+	mapset, _, err := client.Mapsets.Get(ctx, m.MapsetID)
+	checkLive(t, err)
+	if mapset.ID != m.MapsetID || len(mapset.Maps) == 0 {
+		t.Errorf("Get = mapset %d with %d maps, want mapset %d", mapset.ID, len(mapset.Maps), m.MapsetID)
+	}
+
+	global, _, err := client.Scores.ListMapGlobal(ctx, md5)
+	checkLive(t, err)
+	for i := 1; i < len(global); i++ {
+		if global[i].PerformanceRating > global[i-1].PerformanceRating {
+			t.Errorf("ListMapGlobal is not ordered by rating at %d", i)
+			break
+		}
+	}
+}
+
+func TestLiveLeaderboard(t *testing.T) {
+	client := liveClient(t)
+
+	// WhatIf relies on leaderboard pages holding 50 users ordered by rank.
+	lb, _, err := client.Leaderboards.Global(ctx, quaver.GameMode4K, nil)
+	checkLive(t, err)
+	if len(lb.Users) != 50 {
+		t.Errorf("Global returned %d users, want a full page of 50", len(lb.Users))
+	}
+	for i, u := range lb.Users {
+		if u.Statistics4K.Ranks.Global != i+1 {
+			t.Errorf("user %d on the first page has rank %d", i, u.Statistics4K.Ranks.Global)
+			break
+		}
+	}
+}
+
+func TestLiveServerStats(t *testing.T) {
+	client := liveClient(t)
+
+	stats, _, err := client.ServerStats.Get(ctx)
+	checkLive(t, err)
+	if stats.TotalUsers == 0 || stats.TotalMapsets == 0 {
+		t.Errorf("Get = %+v, want users and mapsets", stats)
+	}
+}
@@ -63,43 +63,43 @@ type MapModeration struct {
 	} `json:"replies"`
 }
 
-func (s *MapsService) get(ctx context.Context, query string) (*Map, error) {
+func (s *MapsService) get(ctx context.Context, query string) (*Map, *Response, error) {
 	url := fmt.Sprintf("map/%v", query)
 
 	var r struct {
 		Map *Map `json:"map"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Map, nil
+	return r.Map, resp, nil
 }
 
 // GetByMD5 retrieves info about a given map by its MD5 hash.
-func (s *MapsService) GetByMD5(ctx context.Context, md5 string) (*Map, error) {
+func (s *MapsService) GetByMD5(ctx context.Context, md5 string) (*Map, *Response, error) {
 	return s.get(ctx, md5)
 }
 
 // GetByID retrieves info about a given map by its ID.
-func (s *MapsService) GetByID(ctx context.Context, id int) (*Map, error) {
+func (s *MapsService) GetByID(ctx context.Context, id int) (*Map, *Response, error) {
 	return s.get(ctx, strconv.Itoa(id))
 }
 
 // ListMods retrieves a list of mods on a given map.
-func (s *MapsService) ListMods(ctx context.Context, mapID int) ([]*MapModeration, error) {
+func (s *MapsService) ListMods(ctx context.Context, mapID int) ([]*MapModeration, *Response, error) {
 	url := fmt.Sprintf("map/%v/mods", mapID)
 
 	var r struct {
 		Mods []*MapModeration `json:"mods"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Mods, nil
+	return r.Mods, resp, nil
 }
@@ -0,0 +1,44 @@
+package quaver_test
+
+import (
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestMapsGet(t *testing.T) {
+	client, _ := seededClient(t)
+
+	m, _, err := client.Maps.GetByID(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MD5 != md5Ranked4K || m.MapsetID != 10 || m.RankedStatus != 2 {
+		t.Errorf("GetByID = %+v, want the ranked 4K map of mapset 10", m)
+	}
+
+	m, _, err = client.Maps.GetByMD5(ctx, md5Ranked7K)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ID != 101 || m.GameMode != int(quaver.GameMode7K) {
+		t.Errorf("GetByMD5 = %+v, want the 7K map 101", m)
+	}
+
+	_, _, err = client.Maps.GetByID(ctx, 404)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("GetByID of a missing map = %v, want a not found error", err)
+	}
+}
+
+func TestMapsListMods(t *testing.T) {
+	client, _ := seededClient(t)
+
+	mods, _, err := client.Maps.ListMods(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mods) != 1 || mods[0].Comment != "Nice map" || mods[0].AuthorId != 2 {
+		t.Errorf("ListMods = %+v, want one comment by user 2", mods)
+	}
+}
@@ -66,57 +66,57 @@ type MapsetSearchOptions struct {
 }
 
 // Get retrieves information about a mapset.
-func (s *MapsetsService) Get(ctx context.Context, id int) (*MapsetWithUser, error) {
+func (s *MapsetsService) Get(ctx context.Context, id int) (*MapsetWithUser, *Response, error) {
 	url := fmt.Sprintf("mapset/%v", id)
 
 	var r struct {
 		Mapset MapsetWithUser `json:"mapset"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return &r.Mapset, nil
+	return &r.Mapset, resp, nil
 }
 
 // ListRanked returns a list of the ids of all the ranked mapsets.
-func (s *MapsetsService) ListRanked(ctx context.Context) ([]*int, error) {
+func (s *MapsetsService) ListRanked(ctx context.Context) ([]*int, *Response, error) {
 	url := fmt.Sprintf("mapset/ranked")
 
 	var r struct {
 		RankedMapsets []*int `json:"ranked_mapsets"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.RankedMapsets, nil
+	return r.RankedMapsets, resp, nil
 }
 
 // ListOffsets returns a list of all mapsets that have online offsets.
-func (s *MapsetsService) ListOffsets(ctx context.Context) ([]*Offset, error) {
+func (s *MapsetsService) ListOffsets(ctx context.Context) ([]*Offset, *Response, error) {
 	url := fmt.Sprintf("mapset/offsets")
 
 	var r struct {
 		OnlineOffsets []*Offset `json:"online_offsets"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.OnlineOffsets, nil
+	return r.OnlineOffsets, resp, nil
 }
 
-func (s *MapsetsService) Search(ctx context.Context, opts *MapsetSearchOptions) ([]*Mapset, error) {
+func (s *MapsetsService) Search(ctx context.Context, opts *MapsetSearchOptions) ([]*Mapset, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("mapset/search?%v", v.Encode())
@@ -125,10 +125,29 @@ func (s *MapsetsService) Search(ctx context.Context, opts *MapsetSearchOptions)
 		Mapsets []*Mapset `json:"mapsets"`
 	}
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Mapsets, nil
+	var page *ListOptions
+	if opts != nil {
+		page = &opts.ListOptions
+	}
+	resp.setPage(page, len(r.Mapsets), 0)
+
+	return r.Mapsets, resp, nil
+}
+
+// SearchPager returns a pager over all mapsets matching the search options.
+func (s *MapsetsService) SearchPager(opts *MapsetSearchOptions) *Pager[*Mapset] {
+	var o MapsetSearchOptions
+	if opts != nil {
+		o = *opts
+	}
+	return newPager(&o.ListOptions, func(ctx context.Context, page int) ([]*Mapset, *Response, error) {
+		q := o
+		q.Page = page
+		return s.Search(ctx, &q)
+	})
 }
@@ -0,0 +1,76 @@
+package quaver_test
+
+import (
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestMapsetsGet(t *testing.T) {
+	client, _ := seededClient(t)
+
+	m, _, err := client.Mapsets.Get(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Title != "Ranked Song" || len(m.Maps) != 2 || m.User.ID != 1 {
+		t.Errorf("Get = %+v, want Ranked Song by user 1 with 2 maps", m.MapsetCompact)
+	}
+	if !m.DateSubmitted.Equal(epoch) {
+		t.Errorf("DateSubmitted = %v, want %v", m.DateSubmitted, epoch)
+	}
+
+	_, _, err = client.Mapsets.Get(ctx, 404)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("Get of a missing mapset = %v, want a not found error", err)
+	}
+}
+
+func TestMapsetsListRanked(t *testing.T) {
+	client, _ := seededClient(t)
+
+	ids, _, err := client.Mapsets.ListRanked(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || *ids[0] != 10 {
+		t.Errorf("ListRanked returned %d mapsets, want only mapset 10", len(ids))
+	}
+}
+
+func TestMapsetsListOffsets(t *testing.T) {
+	client, _ := seededClient(t)
+
+	offsets, _, err := client.Mapsets.ListOffsets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offsets) != 1 || offsets[0].ID != 10 || offsets[0].Offset != -20 {
+		t.Errorf("ListOffsets = %+v, want -20 for mapset 10", offsets)
+	}
+}
+
+func TestMapsetsSearch(t *testing.T) {
+	client, _ := seededClient(t)
+
+	mapsets, resp, err := client.Mapsets.Search(ctx, &quaver.MapsetSearchOptions{Search: "camellia"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mapsets) != 1 || mapsets[0].ID != 10 {
+		t.Errorf("Search = %+v, want mapset 10", mapsets)
+	}
+	// Search has no fixed page size, so only an empty page ends it.
+	if resp.NextPage != 1 {
+		t.Errorf("NextPage = %d, want 1", resp.NextPage)
+	}
+
+	p := client.Mapsets.SearchPager(&quaver.MapsetSearchOptions{Search: "pack"})
+	mapsets, err = p.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mapsets) != 60 || mapsets[59].ID != 259 {
+		t.Errorf("SearchPager returned %d mapsets, want the 60 packs", len(mapsets))
+	}
+}
@@ -0,0 +1,202 @@
+package quaver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidModifiers is returned when a set of modifiers cannot be played
+// together.
+var ErrInvalidModifiers = errors.New("quaver: invalid modifiers")
+
+// allModifiers holds every known modifier bit.
+const allModifiers = ModifierNoMiss<<1 - 1
+
+var modifierRates = map[Modifier]float64{
+	ModifierSpeed05X:  0.5,
+	ModifierSpeed055X: 0.55,
+	ModifierSpeed06X:  0.6,
+	ModifierSpeed065X: 0.65,
+	ModifierSpeed07X:  0.7,
+	ModifierSpeed075X: 0.75,
+	ModifierSpeed08X:  0.8,
+	ModifierSpeed085X: 0.85,
+	ModifierSpeed09X:  0.9,
+	ModifierSpeed095X: 0.95,
+	ModifierSpeed105X: 1.05,
+	ModifierSpeed11X:  1.1,
+	ModifierSpeed115X: 1.15,
+	ModifierSpeed12X:  1.2,
+	ModifierSpeed125X: 1.25,
+	ModifierSpeed13X:  1.3,
+	ModifierSpeed135X: 1.35,
+	ModifierSpeed14X:  1.4,
+	ModifierSpeed145X: 1.45,
+	ModifierSpeed15X:  1.5,
+	ModifierSpeed155X: 1.55,
+	ModifierSpeed16X:  1.6,
+	ModifierSpeed165X: 1.65,
+	ModifierSpeed17X:  1.7,
+	ModifierSpeed175X: 1.75,
+	ModifierSpeed18X:  1.8,
+	ModifierSpeed185X: 1.85,
+	ModifierSpeed19X:  1.9,
+	ModifierSpeed195X: 1.95,
+	ModifierSpeed20X:  2,
+}
+
+// Rate returns the audio rate set by the modifiers, or 1 if none is set. If
+// several rates are set, the one with the lowest bit wins.
+func (m Modifier) Rate() float64 {
+	for _, mod := range (m & RateModifiers).Flags() {
+		if r, ok := modifierRates[mod]; ok {
+			return r
+		}
+	}
+	return 1
+}
+
+// ModifierFromRate returns the rate modifier for rate, or ModifierNone for
+// 1.0x.
+func ModifierFromRate(rate float64) (Modifier, error) {
+	if math.Abs(rate-1) < 1e-9 {
+		return ModifierNone, nil
+	}
+	for _, mod := range RateModifiers.Flags() {
+		if math.Abs(rate-modifierRates[mod]) < 1e-9 {
+			return mod, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: no modifier for rate %v", ErrInvalidModifiers, rate)
+}
+
+var modifierNames = map[Modifier]string{
+	ModifierNoSliderVelocity: "NSV",
+	ModifierStrict:           "STR",
+	ModifierChill:            "CHL",
+	ModifierNoPause:          "NP",
+	ModifierAutoplay:         "AP",
+	ModifierPaused:           "PS",
+	ModifierNoFail:           "NF",
+	ModifierNoLongNotes:      "NLN",
+	ModifierRandomize:        "RND",
+	ModifierInverse:          "INV",
+	ModifierFullLN:           "FLN",
+	ModifierMirror:           "MR",
+	ModifierCoop:             "CO",
+	ModifierHealthAdjust:     "HA",
+	ModifierNoMiss:           "NM",
+}
+
+// Flags returns each modifier in m, in order of their bit values.
+func (m Modifier) Flags() []Modifier {
+	var flags []Modifier
+	for b := uint64(m); b != 0; b &= b - 1 {
+		flags = append(flags, Modifier(1)<<bits.TrailingZeros64(b))
+	}
+	return flags
+}
+
+// String returns the in-game abbreviations of the modifiers, with the rate
+// first, such as "1.2x, NLN, MR". Unknown bits are written as a number.
+func (m Modifier) String() string {
+	if m == ModifierNone {
+		return "None"
+	}
+
+	var names []string
+	for _, f := range m.Flags() {
+		if r, ok := modifierRates[f]; ok {
+			names = append(names, strconv.FormatFloat(r, 'f', -1, 64)+"x")
+		}
+	}
+	for _, f := range m.Flags() {
+		if name, ok := modifierNames[f]; ok {
+			names = append(names, name)
+		}
+	}
+	if unknown := m &^ allModifiers; unknown != 0 {
+		names = append(names, strconv.FormatInt(int64(unknown), 10))
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseModifier parses modifiers written as by Modifier.String. Abbreviations
+// are matched case-insensitively and may be separated by commas, spaces or
+// plus signs. Rates are written as a number followed by "x", bits without an
+// abbreviation as a decimal number, and "None" or an empty string is
+// ModifierNone.
+func ParseModifier(s string) (Modifier, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '+' || unicode.IsSpace(r)
+	})
+
+	var m Modifier
+	for _, f := range fields {
+		if strings.EqualFold(f, "None") {
+			continue
+		}
+
+		if v, ok := strings.CutSuffix(strings.ToLower(f), "x"); ok {
+			r, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return 0, fmt.Errorf("%w: unknown modifier %q", ErrInvalidModifiers, f)
+			}
+			mod, err := ModifierFromRate(r)
+			if err != nil {
+				return 0, err
+			}
+			m |= mod
+			continue
+		}
+
+		if n, err := strconv.ParseInt(f, 10, 64); err == nil {
+			m |= Modifier(n)
+			continue
+		}
+
+		i := slices.IndexFunc(modifierFlags, func(mod Modifier) bool {
+			return strings.EqualFold(modifierNames[mod], f)
+		})
+		if i < 0 {
+			return 0, fmt.Errorf("%w: unknown modifier %q", ErrInvalidModifiers, f)
+		}
+		m |= modifierFlags[i]
+	}
+	return m, nil
+}
+
+// modifierFlags holds the modifiers that have an abbreviation.
+var modifierFlags = (allModifiers &^ RateModifiers).Flags()
+
+// exclusiveModifiers are pairs of modifiers that cannot be played together.
+var exclusiveModifiers = [][2]Modifier{
+	{ModifierStrict, ModifierChill},
+	{ModifierMirror, ModifierRandomize},
+	{ModifierNoLongNotes, ModifierFullLN},
+	{ModifierNoLongNotes, ModifierInverse},
+	{ModifierFullLN, ModifierInverse},
+}
+
+// Validate returns an error wrapping ErrInvalidModifiers if m holds unknown
+// bits, more than one rate, or modifiers that cannot be played together.
+func (m Modifier) Validate() error {
+	if unknown := m &^ allModifiers; unknown != 0 {
+		return fmt.Errorf("%w: unknown modifier bits %d", ErrInvalidModifiers, int64(unknown))
+	}
+	if rates := m & RateModifiers; bits.OnesCount64(uint64(rates)) > 1 {
+		return fmt.Errorf("%w: more than one rate (%v)", ErrInvalidModifiers, rates)
+	}
+	for _, pair := range exclusiveModifiers {
+		if m&pair[0] != 0 && m&pair[1] != 0 {
+			return fmt.Errorf("%w: %v cannot be combined with %v", ErrInvalidModifiers, pair[0], pair[1])
+		}
+	}
+	return nil
+}
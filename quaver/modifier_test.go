@@ -0,0 +1,146 @@
+package quaver
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestModifierRate(t *testing.T) {
+	tests := []struct {
+		mods Modifier
+		want float64
+	}{
+		{ModifierNone, 1},
+		{ModifierMirror, 1},
+		{ModifierSpeed15X | ModifierNoFail, 1.5},
+		{ModifierSpeed05X | ModifierSpeed20X, 0.5},
+		{ModifierSpeed12X | ModifierSpeed08X, 0.8},
+	}
+	for _, tt := range tests {
+		// Rate used to range over a map, so repeat to catch any randomness.
+		for range 20 {
+			if got := tt.mods.Rate(); got != tt.want {
+				t.Fatalf("%d.Rate() = %v, want %v", tt.mods, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestModifierFromRate(t *testing.T) {
+	for _, mod := range RateModifiers.Flags() {
+		got, err := ModifierFromRate(mod.Rate())
+		if err != nil || got != mod {
+			t.Errorf("ModifierFromRate(%v) = %d, %v, want %d", mod.Rate(), got, err, mod)
+		}
+	}
+
+	got, err := ModifierFromRate(1)
+	if err != nil || got != ModifierNone {
+		t.Errorf("ModifierFromRate(1) = %d, %v, want ModifierNone", got, err)
+	}
+	if _, err := ModifierFromRate(1.02); err == nil {
+		t.Error("ModifierFromRate(1.02) did not fail")
+	}
+}
+
+func TestModifierString(t *testing.T) {
+	tests := []struct {
+		mods Modifier
+		want string
+	}{
+		{ModifierNone, "None"},
+		{ModifierMirror, "MR"},
+		{ModifierSpeed12X | ModifierNoLongNotes | ModifierMirror, "1.2x, NLN, MR"},
+		{ModifierSpeed05X, "0.5x"},
+		{ModifierSpeed20X | ModifierNoFail, "2x, NF"},
+		{allModifiers + 1, strconv.FormatInt(int64(allModifiers+1), 10)},
+		{ModifierChill | (allModifiers+1)<<2, "CHL, " + strconv.FormatInt(int64((allModifiers+1)<<2), 10)},
+	}
+	for _, tt := range tests {
+		if got := tt.mods.String(); got != tt.want {
+			t.Errorf("%d.String() = %q, want %q", int64(tt.mods), got, tt.want)
+		}
+	}
+}
+
+func TestParseModifierRoundTrip(t *testing.T) {
+	unknown := (allModifiers + 1) << 3
+	mods := []Modifier{
+		ModifierNone,
+		ModifierSpeed15X | ModifierMirror | ModifierNoFail,
+		allModifiers &^ RateModifiers,
+		unknown,
+		ModifierSpeed085X | ModifierStrict | unknown | unknown<<5,
+		// The sign bit is written as a negative number.
+		ModifierMirror | math.MinInt64,
+	}
+	for _, mod := range allModifiers.Flags() {
+		mods = append(mods, mod)
+	}
+
+	for _, mod := range mods {
+		s := mod.String()
+		got, err := ParseModifier(s)
+		if err != nil || got != mod {
+			t.Errorf("ParseModifier(%q) = %d, %v, want %d", s, int64(got), err, int64(mod))
+		}
+	}
+}
+
+func TestParseModifier(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Modifier
+	}{
+		{"", ModifierNone},
+		{"none", ModifierNone},
+		{"mr+nf", ModifierMirror | ModifierNoFail},
+		{"1.5X  NLN", ModifierSpeed15X | ModifierNoLongNotes},
+		{"1x", ModifierNone},
+		{"MR, MR", ModifierMirror},
+		// Numbers may name known bits too.
+		{strconv.FormatInt(int64(ModifierMirror), 10), ModifierMirror},
+	}
+	for _, tt := range tests {
+		got, err := ParseModifier(tt.s)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseModifier(%q) = %d, %v, want %d", tt.s, int64(got), err, int64(tt.want))
+		}
+	}
+
+	for _, s := range []string{"XYZ", "1.02x", "fastx", "1.5", "12abc"} {
+		if _, err := ParseModifier(s); !errors.Is(err, ErrInvalidModifiers) {
+			t.Errorf("ParseModifier(%q) = %v, want ErrInvalidModifiers", s, err)
+		}
+	}
+}
+
+func TestModifierValidate(t *testing.T) {
+	tests := []struct {
+		mods    Modifier
+		wantErr bool
+	}{
+		{ModifierNone, false},
+		{ModifierSpeed12X | ModifierMirror | ModifierNoLongNotes, false},
+		{allModifiers &^ RateModifiers &^ (ModifierChill | ModifierRandomize | ModifierFullLN | ModifierInverse), false},
+		{ModifierMirror | ModifierRandomize, true},
+		{ModifierStrict | ModifierChill, true},
+		{ModifierNoLongNotes | ModifierFullLN, true},
+		{ModifierNoLongNotes | ModifierInverse, true},
+		{ModifierFullLN | ModifierInverse, true},
+		{ModifierSpeed12X | ModifierSpeed15X, true},
+		{ModifierSpeed05X | ModifierSpeed20X | ModifierMirror, true},
+		{allModifiers + 1, true},
+	}
+	for _, tt := range tests {
+		err := tt.mods.Validate()
+		if tt.wantErr && !errors.Is(err, ErrInvalidModifiers) {
+			t.Errorf("%v: Validate() = %v, want ErrInvalidModifiers", tt.mods, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%v: Validate() = %v, want nil", tt.mods, err)
+		}
+	}
+}
@@ -60,25 +60,25 @@ type MultiplayerMatchScore struct {
 	User              *UserCompact `json:"user"`
 }
 
-func (s *MultiplayerService) GetGame(ctx context.Context, id int) (*MultiplayerGame, error) {
+func (s *MultiplayerService) GetGame(ctx context.Context, id int) (*MultiplayerGame, *Response, error) {
 	url := fmt.Sprintf("multiplayer/game/%v", id)
 
 	var r struct {
 		Game *MultiplayerGame `json:"game"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Game, nil
+	return r.Game, resp, nil
 }
 
-func (s *MultiplayerService) ListGames(ctx context.Context, opts *ListOptions) ([]*MultiplayerGameCompact, error) {
+func (s *MultiplayerService) ListGames(ctx context.Context, opts *ListOptions) ([]*MultiplayerGameCompact, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("multiplayer/games?%v", v.Encode())
@@ -87,10 +87,19 @@ func (s *MultiplayerService) ListGames(ctx context.Context, opts *ListOptions) (
 		Games []*MultiplayerGameCompact `json:"games"`
 	}
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Games, nil
+	resp.setPage(opts, len(r.Games), 0)
+
+	return r.Games, resp, nil
+}
+
+// ListGamesPager returns a pager over all multiplayer games.
+func (s *MultiplayerService) ListGamesPager(opts *ListOptions) *Pager[*MultiplayerGameCompact] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*MultiplayerGameCompact, *Response, error) {
+		return s.ListGames(ctx, &ListOptions{Page: page})
+	})
 }
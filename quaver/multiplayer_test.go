@@ -0,0 +1,44 @@
+package quaver_test
+
+import (
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestMultiplayerGetGame(t *testing.T) {
+	client, _ := seededClient(t)
+
+	g, _, err := client.Multiplayer.GetGame(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.ID != 2 || g.Name != "Game" || !g.TimeCreated.Equal(epoch) {
+		t.Errorf("GetGame = %+v, want game 2", g)
+	}
+
+	_, _, err = client.Multiplayer.GetGame(ctx, 404)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("GetGame of a missing game = %v, want a not found error", err)
+	}
+}
+
+func TestMultiplayerListGames(t *testing.T) {
+	client, _ := seededClient(t)
+
+	games, resp, err := client.Multiplayer.ListGames(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 3 || resp.NextPage != 1 {
+		t.Errorf("ListGames returned %d games and next page %d, want 3 and 1", len(games), resp.NextPage)
+	}
+
+	games, err = client.Multiplayer.ListGamesPager(nil).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 3 || games[2].ID != 3 {
+		t.Errorf("ListGamesPager returned %d games, want 3", len(games))
+	}
+}
@@ -0,0 +1,82 @@
+package quaver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*Client) error
+
+// WithBaseURL sets the URL the client sends API requests to, such as a mirror
+// of https://api.quavergame.com/v2/.
+func WithBaseURL(rawURL string) Option {
+	return func(c *Client) error {
+		if !strings.HasSuffix(rawURL, "/") {
+			rawURL += "/"
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("quaver: invalid base URL: %w", err)
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests. The client is
+// copied, so later changes to it have no effect.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return fmt.Errorf("quaver: nil HTTP client")
+		}
+		httpClient2 := *httpClient
+		c.client = &httpClient2
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) error {
+		c.header.Add(key, value)
+		return nil
+	}
+}
+
+// WithToken sends token as a bearer token with every request.
+func WithToken(token string) Option {
+	return func(c *Client) error {
+		c.header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// WithRetry sets the retry policy of the client. A nil policy disables
+// retrying.
+func WithRetry(policy *RetryPolicy) Option {
+	return func(c *Client) error {
+		c.Retry = policy
+		return nil
+	}
+}
+
+// WithRateLimit limits the client to rps requests per second, allowing bursts
+// of up to burst requests.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) error {
+		c.SetRateLimit(rps, burst)
+		return nil
+	}
+}
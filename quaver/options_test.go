@@ -0,0 +1,84 @@
+package quaver_test
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestOptionsHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []quaver.Option
+		header string
+		want   []string
+	}{
+		{"user agent", []quaver.Option{quaver.WithUserAgent("my-bot/1.0")}, "User-Agent", []string{"my-bot/1.0"}},
+		{"header", []quaver.Option{quaver.WithHeader("X-Test", "1")}, "X-Test", []string{"1"}},
+		{"repeated header", []quaver.Option{quaver.WithHeader("X-Test", "1"), quaver.WithHeader("X-Test", "2")}, "X-Test", []string{"1", "2"}},
+		{"token", []quaver.Option{quaver.WithToken("secret")}, "Authorization", []string{"Bearer secret"}},
+		{"no token", nil, "Authorization", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mux := setup(t, tt.opts...)
+			got := make(chan []string, 2)
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				got <- r.Header.Values(tt.header)
+				_, _ = w.Write([]byte(`{"online_users": 1}`))
+			}
+			mux.HandleFunc("GET /v2/server/stats", handler)
+			mux.HandleFunc("GET /v2/download/map/1", handler)
+
+			// API requests and downloads both carry the headers.
+			if _, _, err := client.ServerStats.Get(ctx); err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if _, err := client.Download.Map(&buf, 1); err != nil {
+				t.Fatal(err)
+			}
+			for _, kind := range []string{"API request", "download"} {
+				if values := <-got; !slices.Equal(values, tt.want) {
+					t.Errorf("%v %v = %q, want %q", kind, tt.header, values, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOptionsDefaultUserAgent(t *testing.T) {
+	client, mux := setup(t)
+	var ua string
+	mux.HandleFunc("GET /v2/server/stats", func(w http.ResponseWriter, r *http.Request) {
+		ua = r.UserAgent()
+		_, _ = w.Write([]byte(`{"online_users": 1}`))
+	})
+
+	if _, _, err := client.ServerStats.Get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ua != client.UserAgent || ua == "" {
+		t.Errorf("User-Agent = %q, want the default %q", ua, client.UserAgent)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	client, err := quaver.NewClient(quaver.WithBaseURL("https://example.com/v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := client.BaseURL.String(); got != "https://example.com/v2/" {
+		t.Errorf("BaseURL = %q, want a trailing slash added", got)
+	}
+
+	if _, err := quaver.NewClient(quaver.WithBaseURL("://bad")); err == nil {
+		t.Error("NewClient with an invalid base URL succeeded")
+	}
+	if _, err := quaver.NewClient(quaver.WithHTTPClient(nil)); err == nil {
+		t.Error("NewClient with a nil HTTP client succeeded")
+	}
+}
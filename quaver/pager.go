@@ -0,0 +1,117 @@
+package quaver
+
+import (
+	"context"
+)
+
+// Pager iterates over every item of a paginated endpoint, fetching pages
+// lazily as they are needed. Iteration stops after a page with no next page,
+// or at the first empty page.
+//
+//	p := client.Users.ListBestScoresPager(userID, quaver.GameMode4K, nil)
+//	for p.Next(ctx) {
+//		score := p.Value()
+//		// ...
+//	}
+//	if err := p.Err(); err != nil {
+//		// ...
+//	}
+type Pager[T any] struct {
+	fetch func(ctx context.Context, page int) ([]T, *Response, error)
+
+	page  int
+	limit int
+	count int
+
+	items []T
+	cur   T
+	resp  *Response
+	err   error
+	done  bool
+	last  bool
+}
+
+func newPager[T any](opts *ListOptions, fetch func(ctx context.Context, page int) ([]T, *Response, error)) *Pager[T] {
+	p := &Pager[T]{fetch: fetch}
+	if opts != nil {
+		p.page = opts.Page
+	}
+	return p
+}
+
+// Limit caps the total number of items returned by the pager. A non-positive
+// n removes the cap.
+func (p *Pager[T]) Limit(n int) *Pager[T] {
+	p.limit = n
+	return p
+}
+
+// Next advances to the next item, fetching the next page if needed. It returns
+// false when there are no more items, the limit is reached, ctx is done or a
+// request fails.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	if p.limit > 0 && p.count >= p.limit {
+		p.done = true
+		return false
+	}
+
+	if len(p.items) == 0 {
+		if p.last {
+			p.done = true
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			p.err = err
+			p.done = true
+			return false
+		}
+
+		items, resp, err := p.fetch(ctx, p.page)
+		p.resp = resp
+		if err != nil {
+			p.err = err
+			p.done = true
+			return false
+		}
+		if len(items) == 0 {
+			p.done = true
+			return false
+		}
+
+		p.items = items
+		p.page = resp.NextPage
+		p.last = resp.NextPage == 0
+	}
+
+	p.cur = p.items[0]
+	p.items = p.items[1:]
+	p.count++
+	return true
+}
+
+// Value returns the current item.
+func (p *Pager[T]) Value() T {
+	return p.cur
+}
+
+// Response returns the response of the most recently fetched page.
+func (p *Pager[T]) Response() *Response {
+	return p.resp
+}
+
+// Err returns the error that stopped iteration, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All collects the remaining items of the pager.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for p.Next(ctx) {
+		items = append(items, p.Value())
+	}
+	return items, p.Err()
+}
@@ -0,0 +1,193 @@
+package quaver_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// paginated registers a handler for pattern serving n items with IDs from 1,
+// size to a page, under key. It returns the number of requests.
+func paginated(mux *http.ServeMux, pattern, key string, n, size int) *atomic.Int32 {
+	var requests atomic.Int32
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		var items []string
+		for id := page*size + 1; id <= min((page+1)*size, n); id++ {
+			items = append(items, fmt.Sprintf(`{"id": %d}`, id))
+		}
+		fmt.Fprintf(w, `{%q: [%v]}`, key, strings.Join(items, ","))
+	})
+	return &requests
+}
+
+func TestPager(t *testing.T) {
+	client, mux := setup(t)
+	activity := paginated(mux, "GET /v2/user/1/activity", "activities", 120, 50)
+
+	items, err := client.Users.ListActivityPager(1, nil).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 120 || items[0].Id != 1 || items[119].Id != 120 {
+		t.Errorf("All returned %d items, want 1 to 120", len(items))
+	}
+
+	// Pages 0 to 2 hold the items, and the empty page 3 ends iteration.
+	if n := activity.Load(); n != 4 {
+		t.Errorf("fetched %d pages, want 4", n)
+	}
+}
+
+// count returns the number of items in a pager, for tests that only need that.
+func count[T any](t *testing.T, p *quaver.Pager[T]) int {
+	t.Helper()
+	items, err := p.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(items)
+}
+
+func TestPagerPageSize(t *testing.T) {
+	client, mux := setup(t)
+
+	tests := []struct {
+		name    string
+		pattern string
+		key     string
+		n, size int
+		count   func(t *testing.T) int
+		pages   int32
+	}{
+		// Scores come 50 to a page, so the short page 2 is the last.
+		{"fixed size", "GET /v2/user/1/scores/1/best", "scores", 120, 50, func(t *testing.T) int {
+			return count(t, client.Users.ListBestScoresPager(1, quaver.GameMode4K, nil))
+		}, 3},
+		// The other endpoints are read up to the first empty page, whatever
+		// their page size.
+		{"clan activity", "GET /v2/clan/1/activity", "activity", 70, 30, func(t *testing.T) int {
+			return count(t, client.Clans.ListActivityPager(1, nil))
+		}, 4},
+		{"mapset search", "GET /v2/mapset/search", "mapsets", 45, 20, func(t *testing.T) int {
+			return count(t, client.Mapsets.SearchPager(nil))
+		}, 4},
+		{"multiplayer games", "GET /v2/multiplayer/games", "games", 25, 10, func(t *testing.T) int {
+			return count(t, client.Multiplayer.ListGamesPager(nil))
+		}, 4},
+		{"user activity", "GET /v2/user/2/activity", "activities", 60, 20, func(t *testing.T) int {
+			return count(t, client.Users.ListActivityPager(2, nil))
+		}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := paginated(mux, tt.pattern, tt.key, tt.n, tt.size)
+			if got := tt.count(t); got != tt.n {
+				t.Errorf("pager returned %d items, want %d", got, tt.n)
+			}
+			if got := requests.Load(); got != tt.pages {
+				t.Errorf("fetched %d pages, want %d", got, tt.pages)
+			}
+		})
+	}
+}
+
+func TestPagerDone(t *testing.T) {
+	client, mux := setup(t)
+	requests := paginated(mux, "GET /v2/user/1/activity", "activities", 10, 50)
+
+	p := client.Users.ListActivityPager(1, nil)
+	if _, err := p.All(ctx); err != nil {
+		t.Fatal(err)
+	}
+	n := requests.Load()
+	if p.Next(ctx) {
+		t.Error("Next after the end returned true")
+	}
+	if requests.Load() != n {
+		t.Error("Next after the end fetched another page")
+	}
+}
+
+func TestPagerLimit(t *testing.T) {
+	client, mux := setup(t)
+	requests := paginated(mux, "GET /v2/user/1/activity", "activities", 120, 50)
+
+	activity, err := client.Users.ListActivityPager(1, nil).Limit(60).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 60 {
+		t.Errorf("All returned %d items, want 60", len(activity))
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("fetched %d pages, want 2", n)
+	}
+}
+
+func TestPagerStartPage(t *testing.T) {
+	client, mux := setup(t)
+	paginated(mux, "GET /v2/user/1/activity", "activities", 120, 50)
+
+	activity, err := client.Users.ListActivityPager(1, &quaver.ListOptions{Page: 1}).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 70 || activity[0].Id != 51 {
+		t.Errorf("All returned %d items starting at %d, want 70 starting at 51", len(activity), activity[0].Id)
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("GET /v2/user/1/activity", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var items []string
+		for id := 1; id <= 50; id++ {
+			items = append(items, fmt.Sprintf(`{"id": %d}`, id))
+		}
+		fmt.Fprintf(w, `{"activities": [%v]}`, strings.Join(items, ","))
+	})
+
+	p := client.Users.ListActivityPager(1, nil)
+	activity, err := p.All(ctx)
+	if !quaver.IsServerError(err) || p.Err() != err {
+		t.Errorf("All error = %v and Err = %v, want a server error", err, p.Err())
+	}
+	if len(activity) != 50 {
+		t.Errorf("All returned %d items before the error, want 50", len(activity))
+	}
+	if p.Next(ctx) {
+		t.Error("Next after an error returned true")
+	}
+}
+
+func TestPagerContext(t *testing.T) {
+	client, mux := setup(t)
+	requests := paginated(mux, "GET /v2/user/1/activity", "activities", 120, 50)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	p := client.Users.ListActivityPager(1, nil)
+	if p.Next(ctx) {
+		t.Error("Next with a done context returned true")
+	}
+	if p.Err() != context.Canceled {
+		t.Errorf("Err = %v, want %v", p.Err(), context.Canceled)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("sent %d requests, want 0", n)
+	}
+}
@@ -0,0 +1,51 @@
+package quaver
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+const (
+	// performanceAccuracyBase is the accuracy at which a play is worth the
+	// difficulty rating of the map.
+	performanceAccuracyBase = 98
+	// performanceAccuracyExponent controls how quickly ratings fall off with
+	// accuracy.
+	performanceAccuracyExponent = 6
+	// overallRatingDecay is the weight of each score in the overall rating
+	// relative to the next best.
+	overallRatingDecay = 0.95
+)
+
+// PerformanceRating returns the performance rating of a play with the given
+// accuracy, from 0 to 100, on a map with the given difficulty rating.
+//
+// Map.DifficultyRating is the difficulty at 1.0x. For plays with a rate
+// modifier, pass the difficulty of the map at that rate, which the
+// difficulty package can calculate.
+func PerformanceRating(difficulty, accuracy float64) float64 {
+	return difficulty * math.Pow(accuracy/performanceAccuracyBase, performanceAccuracyExponent)
+}
+
+// OverallPerformanceRating returns the overall rating of a user with the
+// given score ratings, as in Statistics.OverallPerformanceRating. The best
+// rating counts in full and each following rating is worth 95% of the one
+// before it.
+//
+// Only a user's best score on each map counts, so the ratings are usually
+// those of the scores returned by UsersService.ListBestScores.
+func OverallPerformanceRating(ratings []float64) float64 {
+	ratings = slices.Clone(ratings)
+	slices.SortFunc(ratings, func(a, b float64) int {
+		return cmp.Compare(b, a)
+	})
+
+	var total float64
+	w := 1.0
+	for _, r := range ratings {
+		total += r * w
+		w *= overallRatingDecay
+	}
+	return total
+}
@@ -0,0 +1,86 @@
+package quaver_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestPerformanceRating(t *testing.T) {
+	tests := []struct {
+		difficulty, accuracy float64
+		want                 float64
+	}{
+		// A play at 98% is worth the difficulty of the map.
+		{difficulty: 10, accuracy: 98, want: 10},
+		{difficulty: 31.5, accuracy: 98, want: 31.5},
+		{difficulty: 10, accuracy: 100, want: 10 * math.Pow(100.0/98, 6)},
+		{difficulty: 10, accuracy: 49, want: 10.0 / 64},
+		{difficulty: 10, accuracy: 0, want: 0},
+		{difficulty: 0, accuracy: 100, want: 0},
+	}
+	for _, tt := range tests {
+		got := quaver.PerformanceRating(tt.difficulty, tt.accuracy)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("PerformanceRating(%v, %v) = %v, want %v", tt.difficulty, tt.accuracy, got, tt.want)
+		}
+	}
+}
+
+func TestOverallPerformanceRating(t *testing.T) {
+	twenty := make([]float64, 20)
+	for i := range twenty {
+		twenty[i] = 1
+	}
+
+	tests := []struct {
+		name    string
+		ratings []float64
+		want    float64
+	}{
+		{"nil", nil, 0},
+		{"empty", []float64{}, 0},
+		{"one", []float64{42}, 42},
+		{"two", []float64{100, 100}, 195},
+		{"sorted", []float64{100, 75, 50}, 100 + 75*0.95 + 50*0.95*0.95},
+		{"unsorted", []float64{50, 100, 75}, 100 + 75*0.95 + 50*0.95*0.95},
+		// Equal ratings sum to a geometric series with ratio 0.95.
+		{"equal", twenty, (1 - math.Pow(0.95, 20)) / 0.05},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := slices.Clone(tt.ratings)
+			if got := quaver.OverallPerformanceRating(tt.ratings); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("OverallPerformanceRating(%v) = %v, want %v", tt.ratings, got, tt.want)
+			}
+			if !slices.Equal(tt.ratings, in) {
+				t.Errorf("ratings were reordered to %v", tt.ratings)
+			}
+		})
+	}
+}
+
+// TestLiveOverallPerformanceRating checks OverallPerformanceRating over a
+// real user's best scores against the overall rating the API reports.
+func TestLiveOverallPerformanceRating(t *testing.T) {
+	client := liveClient(t)
+
+	user, _, err := client.Users.GetByID(ctx, liveUserID)
+	checkLive(t, err)
+	scores, err := client.Users.ListBestScoresPager(liveUserID, quaver.GameMode4K, nil).All(ctx)
+	checkLive(t, err)
+	if len(scores) == 0 {
+		t.Fatal("no best scores recorded")
+	}
+
+	var ratings []float64
+	for _, s := range scores {
+		ratings = append(ratings, s.PerformanceRating)
+	}
+	got := quaver.OverallPerformanceRating(ratings)
+	if want := user.Statistics4K.OverallPerformanceRating; math.Abs(got-want) > 0.01 {
+		t.Errorf("OverallPerformanceRating of %d best scores = %v, want %v", len(scores), got, want)
+	}
+}
@@ -36,50 +36,54 @@ type PlaylistSearchResponse struct {
 	User      User       `json:"user"`
 }
 
-func (s *PlaylistsService) Search(ctx context.Context, query string, opts *ListOptions) (*PlaylistSearchResponse, error) {
+func (s *PlaylistsService) Search(ctx context.Context, query string, opts *ListOptions) (*PlaylistSearchResponse, *Response, error) {
 	v, err := qs.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("playlists/search?query=%v&%v", query, v.Encode())
 
 	var r *PlaylistSearchResponse
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r, nil
+	if r != nil {
+		resp.setPage(opts, len(r.Playlists), 0)
+	}
+
+	return r, resp, nil
 }
 
-func (s *PlaylistsService) Get(ctx context.Context, id int) (*Playlist, error) {
+func (s *PlaylistsService) Get(ctx context.Context, id int) (*Playlist, *Response, error) {
 	url := fmt.Sprintf("playlists/%v", id)
 
 	var r struct {
 		Playlist *Playlist `json:"playlist"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Playlist, nil
+	return r.Playlist, resp, nil
 }
 
-func (s *PlaylistsService) ContainsMap(ctx context.Context, playlistID, mapID int) (bool, error) {
+func (s *PlaylistsService) ContainsMap(ctx context.Context, playlistID, mapID int) (bool, *Response, error) {
 	url := fmt.Sprintf("playlists/%v/contains/%v", playlistID, mapID)
 
 	var r struct {
 		Exists bool `json:"exists"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return false, err
+		return false, resp, err
 	}
 
-	return r.Exists, nil
+	return r.Exists, resp, nil
 }
@@ -0,0 +1,56 @@
+package quaver_test
+
+import (
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestPlaylistsGet(t *testing.T) {
+	client, _ := seededClient(t)
+
+	p, _, err := client.Playlists.Get(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Favourites" || p.User.ID != 1 || len(p.Mapsets) != 1 || p.Mapsets[0].Maps[0].Map.ID != 100 {
+		t.Errorf("Get = %+v, want Favourites by user 1 holding map 100", p.PlaylistCompact)
+	}
+
+	_, _, err = client.Playlists.Get(ctx, 404)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("Get of a missing playlist = %v, want a not found error", err)
+	}
+}
+
+func TestPlaylistsSearch(t *testing.T) {
+	client, _ := seededClient(t)
+
+	r, _, err := client.Playlists.Search(ctx, "fav", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Playlists) != 1 {
+		t.Errorf("Search returned %d playlists, want 1", len(r.Playlists))
+	}
+}
+
+func TestPlaylistsContainsMap(t *testing.T) {
+	client, _ := seededClient(t)
+
+	for _, tt := range []struct {
+		mapID int
+		want  bool
+	}{
+		{100, true},
+		{101, false},
+	} {
+		ok, _, err := client.Playlists.ContainsMap(ctx, 3, tt.mapID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.want {
+			t.Errorf("ContainsMap(3, %d) = %v, want %v", tt.mapID, ok, tt.want)
+		}
+	}
+}
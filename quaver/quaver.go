@@ -3,9 +3,14 @@ package quaver
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -20,6 +25,22 @@ type Client struct {
 
 	UserAgent string
 
+	// Retry controls how failed requests are retried. A nil policy disables
+	// retrying.
+	Retry *RetryPolicy
+
+	header http.Header
+
+	cache         Cache
+	downloadCache Cache
+	cacheRules    []cacheRule
+
+	flight flightGroup
+
+	rateMu  sync.Mutex
+	limiter *rate.Limiter
+	rate    Rate
+
 	common service
 
 	Clans        *ClansService
@@ -34,14 +55,19 @@ type Client struct {
 	Users        *UsersService
 }
 
-func NewClient(httpClient *http.Client) *Client {
-	if httpClient == nil {
-		httpClient = &http.Client{}
-	}
-	httpClient2 := *httpClient
-	c := &Client{client: &httpClient2}
+// NewClient returns a new Quaver API client configured by opts.
+func NewClient(opts ...Option) (*Client, error) {
+	c := &Client{}
 	c.initialize()
-	return c
+
+	for _, opt := range opts {
+		err := opt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
 }
 
 func (c *Client) initialize() {
@@ -50,6 +76,9 @@ func (c *Client) initialize() {
 	}
 	c.BaseURL, _ = url.Parse(defaultBaseURL)
 	c.UserAgent = defaultUserAgent
+	c.Retry = DefaultRetryPolicy()
+	c.header = make(http.Header)
+	c.cacheRules = slices.Clone(defaultCacheRules)
 
 	c.common.client = c
 	c.Clans = (*ClansService)(&c.common)
@@ -72,40 +101,115 @@ type ListOptions struct {
 	Page int `url:"page,omitempty"`
 }
 
-// Error represents an error returned by the Quaver API.
+// Error represents an error returned by the Quaver API. It is available as the
+// Payload of an APIError.
 type Error struct {
 	Error string `json:"error"`
 }
 
-func (c *Client) get(ctx context.Context, url string, result interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL.String()+url, nil)
+// newRequest creates a GET request for url with the client's headers applied.
+func (c *Client) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	for k, v := range c.header {
+		req.Header[k] = append([]string(nil), v...)
 	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	return req, nil
+}
 
-	resp, err := c.client.Do(req)
+func (c *Client) get(ctx context.Context, url string, result interface{}) (*Response, error) {
+	req, err := c.newRequest(ctx, c.BaseURL.String()+url)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		var e Error
-		err = json.NewDecoder(resp.Body).Decode(&e)
-		if err != nil {
-			return err
+	key := req.URL.String()
+	ttl := c.cacheTTL(url)
+	useCache := c.cache != nil && ttl > 0
+
+	if useCache && !isCacheBypassed(ctx) {
+		if body, ok := c.cache.Get(key); ok {
+			err = json.Unmarshal(body, result)
+			if err == nil {
+				return newCachedResponse(req), nil
+			}
+			c.cache.Delete(key)
 		}
-		return fmt.Errorf("quaver: %s", e.Error)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	// Concurrent identical requests share one round trip. Each caller decodes
+	// the shared body into its own result, so callers never share values.
+	resp, body, err := c.flight.do(ctx, "GET "+key, func(ctx context.Context) (*Response, []byte, error) {
+		resp, body, err := c.fetch(req.WithContext(ctx))
+		if err == nil && useCache {
+			c.cache.Set(key, body, ttl)
+		}
+		return resp, body, err
+	})
+	if err != nil {
+		return resp, err
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(result)
+	err = json.Unmarshal(body, result)
 	if err != nil {
-		return err
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// fetch sends req and reads the body of a successful response.
+func (c *Client) fetch(req *http.Request) (*Response, []byte, error) {
+	start := time.Now()
+	r, err := c.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer r.Body.Close()
+
+	resp := newResponse(r, time.Since(start))
+
+	err = checkResponse(r)
+	if err != nil {
+		return resp, nil, err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+
+	return resp, body, nil
+}
+
+// checkResponse returns an *APIError if the response has a non-200 status code.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     resp.Request.Method,
+		URL:        resp.Request.URL.String(),
+		Header:     resp.Header,
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil {
+		var payload Error
+		if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+			e.Payload = &payload
+		}
+		e.Body = body
 	}
 
-	return nil
+	return e
 }
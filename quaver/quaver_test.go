@@ -0,0 +1,48 @@
+package quaver_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+var ctx = context.Background()
+
+// setup starts a test server and returns a client talking to it, along with
+// the mux the test registers its handlers on. Paths are relative to the
+// server root, so the API's start with /v2/. Retrying is disabled, so
+// failures are returned directly; opts are applied after the defaults.
+func setup(t *testing.T, opts ...quaver.Option) (*quaver.Client, *http.ServeMux) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	opts = append([]quaver.Option{
+		quaver.WithBaseURL(srv.URL + "/v2/"),
+		quaver.WithRetry(nil),
+	}, opts...)
+	client, err := quaver.NewClient(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, mux
+}
+
+// serve registers a handler for pattern that responds with status and body,
+// and returns the number of requests it receives.
+func serve(mux *http.ServeMux, pattern string, status int, body string) *atomic.Int32 {
+	var n atomic.Int32
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		n.Add(1)
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	})
+	return &n
+}
@@ -0,0 +1,86 @@
+package quaver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	headerRateLimit     = "X-RateLimit-Limit"
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+)
+
+// Rate represents the rate limit reported by the Quaver API.
+type Rate struct {
+	// Limit is the number of requests allowed in the current window.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Reset is the time at which the current window resets.
+	Reset time.Time
+}
+
+// parseRate reads the rate limit headers of a response. Missing headers are
+// left as zero values.
+func parseRate(h http.Header) Rate {
+	var r Rate
+	if v := h.Get(headerRateLimit); v != "" {
+		r.Limit, _ = strconv.Atoi(v)
+	}
+	if v := h.Get(headerRateRemaining); v != "" {
+		r.Remaining, _ = strconv.Atoi(v)
+	}
+	if v := h.Get(headerRateReset); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			r.Reset = time.Unix(secs, 0)
+		}
+	}
+	return r
+}
+
+// SetRateLimit limits the client to rps requests per second, allowing bursts
+// of up to burst requests. The limit is shared by all services. A
+// non-positive rps removes the limit.
+func (c *Client) SetRateLimit(rps float64, burst int) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	if rps <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = rate.NewLimiter(rate.Limit(rps), max(burst, 1))
+}
+
+// Rate returns the rate limit reported by the most recent response.
+func (c *Client) Rate() Rate {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	return c.rate
+}
+
+func (c *Client) rateLimiter() *rate.Limiter {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	return c.limiter
+}
+
+func (c *Client) updateRate(resp *http.Response) {
+	r := parseRate(resp.Header)
+	if r == (Rate{}) {
+		return
+	}
+
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	c.rate = r
+}
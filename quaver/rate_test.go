@@ -0,0 +1,102 @@
+package quaver_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestRate(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("GET /v2/server/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		_, _ = w.Write([]byte(`{"online_users": 1}`))
+	})
+	serve(mux, "GET /v2/user/1", http.StatusOK, `{"user": {"id": 1}}`)
+
+	want := quaver.Rate{Limit: 100, Remaining: 42, Reset: time.Unix(1700000000, 0)}
+
+	_, resp, err := client.ServerStats.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Rate != want {
+		t.Errorf("Response.Rate = %+v, want %+v", resp.Rate, want)
+	}
+	if got := client.Rate(); got != want {
+		t.Errorf("Client.Rate = %+v, want %+v", got, want)
+	}
+
+	// A response without the headers leaves the last known rate in place.
+	_, resp, err = client.Users.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Rate != (quaver.Rate{}) {
+		t.Errorf("Response.Rate = %+v, want the zero value", resp.Rate)
+	}
+	if got := client.Rate(); got != want {
+		t.Errorf("Client.Rate = %+v, want %+v", got, want)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	const (
+		requests = 5
+		interval = 20 * time.Millisecond
+	)
+
+	client, mux := setup(t, quaver.WithRateLimit(float64(time.Second/interval), 1))
+
+	var mu sync.Mutex
+	var times []time.Time
+	mux.HandleFunc("GET /v2/server/stats", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"online_users": 1}`))
+	})
+
+	start := time.Now()
+	for range requests {
+		if _, _, err := client.ServerStats.Get(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(times) != requests {
+		t.Fatalf("server received %d requests, want %d", len(times), requests)
+	}
+	// Request i waits for the token granted i intervals after the first.
+	for i, at := range times {
+		if d, want := at.Sub(start), time.Duration(i)*interval; d < want-time.Millisecond {
+			t.Errorf("request %d arrived after %v, want at least %v", i, d, want)
+		}
+	}
+}
+
+func TestRateLimitDeadline(t *testing.T) {
+	client, mux := setup(t, quaver.WithRateLimit(0.1, 1))
+	n := serve(mux, "GET /v2/server/stats", http.StatusOK, `{"online_users": 1}`)
+
+	if _, _, err := client.ServerStats.Get(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next token is ten seconds away, past the deadline, so the request
+	// fails without being sent.
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.ServerStats.Get(quaver.BypassCache(ctx)); err == nil {
+		t.Error("request past the deadline succeeded")
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+}
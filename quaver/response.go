@@ -0,0 +1,68 @@
+package quaver
+
+import (
+	"net/http"
+	"time"
+)
+
+// Response wraps the http.Response returned by the Quaver API. Its body has
+// already been read and closed.
+type Response struct {
+	*http.Response
+
+	// Rate is the rate limit reported with the response.
+	Rate Rate
+
+	// Elapsed is the time taken by the request, including any retries.
+	Elapsed time.Duration
+
+	// Cached reports whether the response was served from the client's cache,
+	// in which case only the status code and request are set.
+	Cached bool
+
+	// NextPage is the page to request after this one. It is zero once there are
+	// no more pages and for endpoints that are not paginated. Endpoints
+	// without a fixed page size only report the end with an empty page.
+	NextPage int
+}
+
+func newResponse(r *http.Response, elapsed time.Duration) *Response {
+	return &Response{
+		Response: r,
+		Rate:     parseRate(r.Header),
+		Elapsed:  elapsed,
+	}
+}
+
+func newCachedResponse(req *http.Request) *Response {
+	return &Response{
+		Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Request:    req,
+		},
+		Cached: true,
+	}
+}
+
+// Page sizes of the endpoints that always return full pages until the last.
+const (
+	leaderboardPageSize = 50
+	userScoresPageSize  = 50
+)
+
+// setPage fills in the pagination fields of a response to a paginated request
+// that returned n items. An empty page is the last, and so is a page with
+// fewer than size items if the endpoint has a fixed page size. A size of 0
+// means the endpoint's page size is not fixed, so only an empty page ends it.
+func (r *Response) setPage(opts *ListOptions, n, size int) {
+	if n == 0 || n < size {
+		return
+	}
+	if opts == nil {
+		r.NextPage = 1
+		return
+	}
+	r.NextPage = opts.Page + 1
+}
@@ -0,0 +1,98 @@
+package quaver_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestResponse(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("GET /v2/server/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc")
+		time.Sleep(time.Millisecond)
+		_, _ = w.Write([]byte(`{"online_users": 1}`))
+	})
+
+	_, resp, err := client.ServerStats.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Request-Id") != "abc" {
+		t.Errorf("Response = %d with X-Request-Id %q, want 200 and abc", resp.StatusCode, resp.Header.Get("X-Request-Id"))
+	}
+	if got, want := resp.Request.URL.String(), client.BaseURL.String()+"server/stats"; got != want {
+		t.Errorf("Request URL = %v, want %v", got, want)
+	}
+	if resp.Elapsed < time.Millisecond {
+		t.Errorf("Elapsed = %v, want at least 1ms", resp.Elapsed)
+	}
+	if resp.NextPage != 0 {
+		t.Errorf("NextPage = %d for an endpoint without pages", resp.NextPage)
+	}
+}
+
+func TestResponseError(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("GET /v2/server/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, resp, err := client.ServerStats.Get(ctx)
+	if !quaver.IsServerError(err) {
+		t.Errorf("error = %v, want a server error", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Response = %+v, want the 503", resp)
+	}
+}
+
+func TestResponseNextPage(t *testing.T) {
+	client, mux := setup(t)
+	paginated(mux, "GET /v2/user/1/scores/1/best", "scores", 120, 50)
+	paginated(mux, "GET /v2/user/1/activity", "activities", 120, 50)
+
+	best := func(opts *quaver.ListOptions) (*quaver.Response, error) {
+		_, resp, err := client.Users.ListBestScores(ctx, 1, quaver.GameMode4K, opts)
+		return resp, err
+	}
+	activity := func(opts *quaver.ListOptions) (*quaver.Response, error) {
+		_, resp, err := client.Users.ListActivity(ctx, 1, opts)
+		return resp, err
+	}
+
+	tests := []struct {
+		name string
+		list func(*quaver.ListOptions) (*quaver.Response, error)
+		opts *quaver.ListOptions
+		want int
+	}{
+		{"first page", best, nil, 1},
+		{"full page", best, &quaver.ListOptions{Page: 1}, 2},
+		// Scores come 50 to a page, so a short page is the last.
+		{"short page", best, &quaver.ListOptions{Page: 2}, 0},
+		{"empty page", best, &quaver.ListOptions{Page: 3}, 0},
+		// Activity has no fixed page size, so only an empty page is the last.
+		{"short page without a page size", activity, &quaver.ListOptions{Page: 2}, 3},
+		{"empty page without a page size", activity, &quaver.ListOptions{Page: 3}, 0},
+	}
+	for _, tt := range tests {
+		resp, err := tt.list(tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.NextPage != tt.want {
+			t.Errorf("%v: NextPage after %+v = %d, want %d", tt.name, tt.opts, resp.NextPage, tt.want)
+		}
+	}
+
+	p := client.Users.ListActivityPager(1, nil).Limit(60)
+	if _, err := p.All(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if resp := p.Response(); resp == nil || resp.NextPage != 2 {
+		t.Errorf("Pager Response = %+v, want the response to page 1", resp)
+	}
+}
@@ -0,0 +1,162 @@
+package quaver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request,
+	// including the first one. Values below 2 disable retrying.
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between attempts.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// MaxRetryAfter caps the delay a Retry-After header may ask for. Responses
+	// asking for longer are returned without retrying. If zero, MaxBackoff is
+	// the cap.
+	MaxRetryAfter time.Duration
+
+	// RetryableStatus lists the status codes that are retried.
+	RetryableStatus []int
+
+	// ShouldRetry, if set, replaces the default check of whether an attempt
+	// should be retried. Exactly one of resp and err is non-nil.
+	ShouldRetry func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by new clients.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:   3,
+		MinBackoff:    500 * time.Millisecond,
+		MaxBackoff:    10 * time.Second,
+		MaxRetryAfter: time.Minute,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(resp, err)
+	}
+	if err != nil {
+		return isTransientError(err)
+	}
+	return slices.Contains(p.RetryableStatus, resp.StatusCode)
+}
+
+// backoff returns the delay before the given retry, starting at 1. The
+// Retry-After header of resp takes precedence when present, and false is
+// returned if it asks for longer than the policy allows.
+func (p *RetryPolicy) backoff(retry int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			limit := p.MaxRetryAfter
+			if limit <= 0 {
+				limit = p.MaxBackoff
+			}
+			return d, d <= limit
+		}
+	}
+
+	d := p.MinBackoff << (retry - 1)
+	if d > p.MaxBackoff || d <= 0 {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0, true
+	}
+
+	// Full jitter over the upper half of the window keeps concurrent
+	// callers from retrying in lockstep.
+	return d/2 + rand.N(d/2+1), true
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// do sends req, waiting on the client's rate limiter before each attempt and
+// retrying according to its retry policy. The returned response is that of the
+// last attempt.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	policy := c.Retry
+
+	for attempt := 1; ; attempt++ {
+		if limiter := c.rateLimiter(); limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.client.Do(req.Clone(ctx))
+		if resp != nil {
+			c.updateRate(resp)
+		}
+
+		if policy == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait, ok := policy.backoff(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
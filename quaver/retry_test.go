@@ -0,0 +1,154 @@
+package quaver_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// fastRetry is the default retry policy with backoffs short enough for tests.
+func fastRetry() *quaver.RetryPolicy {
+	p := quaver.DefaultRetryPolicy()
+	p.MinBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+// failing registers a handler for pattern that responds with the next of
+// statuses, repeating the last one, and returns the number of requests.
+func failing(mux *http.ServeMux, pattern string, header http.Header, statuses ...int) *atomic.Int32 {
+	var n atomic.Int32
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		i := int(n.Add(1)) - 1
+		status := statuses[min(i, len(statuses)-1)]
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`{"online_users": 1}`))
+		} else {
+			_, _ = w.Write([]byte(`{"error": "failed"}`))
+		}
+	})
+	return &n
+}
+
+func TestRetry(t *testing.T) {
+	client, mux := setup(t, quaver.WithRetry(fastRetry()))
+	n := failing(mux, "GET /v2/server/stats", nil, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+
+	stats, _, err := client.ServerStats.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.OnlineUsers != 1 {
+		t.Errorf("Get = %+v, want 1 online", stats)
+	}
+	if got := n.Load(); got != 3 {
+		t.Errorf("sent %d requests, want 3", got)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	client, mux := setup(t, quaver.WithRetry(fastRetry()))
+	n := failing(mux, "GET /v2/server/stats", nil, http.StatusInternalServerError)
+
+	_, _, err := client.ServerStats.Get(ctx)
+	if !quaver.IsServerError(err) {
+		t.Errorf("error = %v, want a server error", err)
+	}
+	if got := n.Load(); got != 3 {
+		t.Errorf("sent %d requests, want MaxAttempts 3", got)
+	}
+}
+
+func TestRetrySkipsClientErrors(t *testing.T) {
+	client, mux := setup(t, quaver.WithRetry(fastRetry()))
+	n := failing(mux, "GET /v2/server/stats", nil, http.StatusNotFound, http.StatusOK)
+
+	_, _, err := client.ServerStats.Get(ctx)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("error = %v, want a not found error", err)
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	client, mux := setup(t, quaver.WithRetry(fastRetry()))
+	n := failing(mux, "GET /v2/server/stats", http.Header{"Retry-After": {"0"}}, http.StatusTooManyRequests, http.StatusOK)
+
+	_, _, err := client.ServerStats.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := n.Load(); got != 2 {
+		t.Errorf("sent %d requests, want 2", got)
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	client, mux := setup(t, quaver.WithRetry(fastRetry()))
+	n := failing(mux, "GET /v2/server/stats", http.Header{"Retry-After": {"3600"}}, http.StatusTooManyRequests, http.StatusOK)
+
+	start := time.Now()
+	_, _, err := client.ServerStats.Get(ctx)
+	if !quaver.IsRateLimited(err) {
+		t.Errorf("error = %v, want a rate limited error", err)
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("returned after %v, want without waiting", d)
+	}
+}
+
+func TestRetryDeadline(t *testing.T) {
+	policy := fastRetry()
+	policy.MinBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	client, mux := setup(t, quaver.WithRetry(policy))
+	n := failing(mux, "GET /v2/server/stats", nil, http.StatusServiceUnavailable, http.StatusOK)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	// The backoff outlasts the deadline, so the 503 is returned at once.
+	_, _, err := client.ServerStats.Get(ctx)
+	if !quaver.IsServerError(err) {
+		t.Errorf("error = %v, want a server error", err)
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+}
+
+func TestRetryDownload(t *testing.T) {
+	client, mux := setup(t, quaver.WithRetry(fastRetry()))
+
+	var n atomic.Int32
+	mux.HandleFunc("GET /v2/download/replay/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if n.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("replay"))
+	})
+
+	var buf bytes.Buffer
+	_, err := client.Download.Replay(&buf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "replay" || n.Load() != 2 {
+		t.Errorf("Replay = %q after %d requests, want %q after 2", buf.String(), n.Load(), "replay")
+	}
+}
@@ -45,114 +45,134 @@ type ScoreWithUser struct {
 }
 
 // ListMapGlobal returns the top 50 scores for a map.
-func (s *ScoresService) ListMapGlobal(ctx context.Context, md5 string) ([]*ScoreWithUser, error) {
+func (s *ScoresService) ListMapGlobal(ctx context.Context, md5 string) ([]*ScoreWithUser, *Response, error) {
 	url := fmt.Sprintf("scores/%v/global", md5)
 
 	var r struct {
 		Scores []*ScoreWithUser `json:"scores"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Scores, nil
+	return r.Scores, resp, nil
 }
 
 // ListMapGlobalWithMods returns the top 50 scores of a map with given modifiers.
-func (s *ScoresService) ListMapGlobalWithMods(ctx context.Context, md5 string, mods Modifier) ([]*ScoreWithUser, error) {
-	url := fmt.Sprintf("scores/%v/mods/%v", md5, mods)
+func (s *ScoresService) ListMapGlobalWithMods(ctx context.Context, md5 string, mods Modifier) ([]*ScoreWithUser, *Response, error) {
+	err := mods.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	url := fmt.Sprintf("scores/%v/mods/%d", md5, mods)
 
 	var r struct {
 		Scores []*ScoreWithUser `json:"scores"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Scores, nil
+	return r.Scores, resp, nil
 }
 
 // ListMapGlobalWithRate returns the top 50 scores on a map with a given speed modifier.
-func (s *ScoresService) ListMapGlobalWithRate(ctx context.Context, md5 string, mods Modifier) ([]*ScoreWithUser, error) {
+func (s *ScoresService) ListMapGlobalWithRate(ctx context.Context, md5 string, mods Modifier) ([]*ScoreWithUser, *Response, error) {
 	if !mods.hasRateModifiers() {
-		return nil, fmt.Errorf("quaver: modifiers must be rate modifiers")
+		return nil, nil, fmt.Errorf("quaver: modifiers must be rate modifiers")
+	}
+
+	err := mods.Validate()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	url := fmt.Sprintf("scores/%v/rate/%v", md5, mods)
+	url := fmt.Sprintf("scores/%v/rate/%d", md5, mods)
 
 	var r struct {
 		Scores []*ScoreWithUser `json:"scores"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Scores, nil
+	return r.Scores, resp, nil
 }
 
-func (s *ScoresService) listUserMap(ctx context.Context, endpoint string, md5 string, userID int) (*ScoreWithUser, error) {
+func (s *ScoresService) listUserMap(ctx context.Context, endpoint string, md5 string, userID int) (*ScoreWithUser, *Response, error) {
 	url := fmt.Sprintf("scores/%v/%v/%v", md5, userID, endpoint)
 
 	var r struct {
 		Score *ScoreWithUser `json:"score"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Score, nil
+	return r.Score, resp, nil
 }
 
 // ListUserMapBest eturns the personal best global score for a user on a given map.
-func (s *ScoresService) ListUserMapBest(ctx context.Context, md5 string, userID int) (*ScoreWithUser, error) {
+func (s *ScoresService) ListUserMapBest(ctx context.Context, md5 string, userID int) (*ScoreWithUser, *Response, error) {
 	return s.listUserMap(ctx, "global", md5, userID)
 }
 
 // ListUserMapAll returns the personal best (all scoreboard) score for a user on a given map.
-func (s *ScoresService) ListUserMapAll(ctx context.Context, md5 string, userID int) (*ScoreWithUser, error) {
+func (s *ScoresService) ListUserMapAll(ctx context.Context, md5 string, userID int) (*ScoreWithUser, *Response, error) {
 	return s.listUserMap(ctx, "all", md5, userID)
 }
 
 // ListUserMapBestWithMods returns a user’s personal best score on a map with given mods.
-func (s *ScoresService) ListUserMapBestWithMods(ctx context.Context, md5 string, userID int, mods Modifier) (*ScoreWithUser, error) {
-	url := fmt.Sprintf("scores/%v/%v/mods/%v", md5, userID, mods)
+func (s *ScoresService) ListUserMapBestWithMods(ctx context.Context, md5 string, userID int, mods Modifier) (*ScoreWithUser, *Response, error) {
+	err := mods.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	url := fmt.Sprintf("scores/%v/%v/mods/%d", md5, userID, mods)
 
 	var r struct {
 		Score *ScoreWithUser `json:"score"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Score, nil
+	return r.Score, resp, nil
 }
 
 // ListUserMapBestWithRate returns a user’s personal best score on a map with given rate modifiers.
-func (s *ScoresService) ListUserMapBestWithRate(ctx context.Context, md5 string, userID int, mods Modifier) (*ScoreWithUser, error) {
+func (s *ScoresService) ListUserMapBestWithRate(ctx context.Context, md5 string, userID int, mods Modifier) (*ScoreWithUser, *Response, error) {
 	if !mods.hasRateModifiers() {
-		return nil, fmt.Errorf("quaver: modifiers must be rate modifiers")
+		return nil, nil, fmt.Errorf("quaver: modifiers must be rate modifiers")
+	}
+
+	err := mods.Validate()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	url := fmt.Sprintf("scores/%v/%v/mods/%v", md5, userID, mods)
+	url := fmt.Sprintf("scores/%v/%v/mods/%d", md5, userID, mods)
 
 	var r struct {
 		Score *ScoreWithUser `json:"score"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Score, nil
+	return r.Score, resp, nil
 }
@@ -0,0 +1,91 @@
+package quaver_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func scoreIDs(scores []*quaver.ScoreWithUser) []int {
+	var ids []int
+	for _, s := range scores {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func TestScoresListMapGlobal(t *testing.T) {
+	client, _ := seededClient(t)
+
+	scores, _, err := client.Scores.ListMapGlobal(ctx, md5Ranked4K)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := scoreIDs(scores), []int{4, 1, 2}; !slices.Equal(got, want) {
+		t.Errorf("ListMapGlobal = %v, want %v", got, want)
+	}
+	if scores[0].User.Username != "Hexa" {
+		t.Errorf("top score by %q, want Hexa", scores[0].User.Username)
+	}
+
+	scores, _, err = client.Scores.ListMapGlobalWithMods(ctx, md5Ranked4K, quaver.ModifierMirror)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := scoreIDs(scores), []int{2}; !slices.Equal(got, want) {
+		t.Errorf("ListMapGlobalWithMods = %v, want %v", got, want)
+	}
+
+	scores, _, err = client.Scores.ListMapGlobalWithRate(ctx, md5Ranked4K, quaver.ModifierSpeed12X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := scoreIDs(scores), []int{4}; !slices.Equal(got, want) {
+		t.Errorf("ListMapGlobalWithRate = %v, want %v", got, want)
+	}
+
+	_, _, err = client.Scores.ListMapGlobalWithRate(ctx, md5Ranked4K, quaver.ModifierMirror)
+	if err == nil {
+		t.Error("ListMapGlobalWithRate without a rate modifier succeeded")
+	}
+}
+
+func TestScoresListUserMap(t *testing.T) {
+	client, _ := seededClient(t)
+
+	tests := []struct {
+		name string
+		get  func() (*quaver.ScoreWithUser, *quaver.Response, error)
+		want int
+	}{
+		{"best", func() (*quaver.ScoreWithUser, *quaver.Response, error) {
+			return client.Scores.ListUserMapBest(ctx, md5Ranked4K, 1)
+		}, 1},
+		{"all", func() (*quaver.ScoreWithUser, *quaver.Response, error) {
+			return client.Scores.ListUserMapAll(ctx, md5Ranked4K, 1)
+		}, 1},
+		{"mods", func() (*quaver.ScoreWithUser, *quaver.Response, error) {
+			return client.Scores.ListUserMapBestWithMods(ctx, md5Ranked4K, 1, quaver.ModifierSpeed12X)
+		}, 3},
+		{"rate", func() (*quaver.ScoreWithUser, *quaver.Response, error) {
+			return client.Scores.ListUserMapBestWithRate(ctx, md5Ranked4K, 1, quaver.ModifierSpeed12X)
+		}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, _, err := tt.get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if score.ID != tt.want || score.User.ID != 1 {
+				t.Errorf("score = %d by user %d, want %d by user 1", score.ID, score.User.ID, tt.want)
+			}
+		})
+	}
+
+	_, _, err := client.Scores.ListUserMapBest(ctx, md5Ranked7K, 2)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("ListUserMapBest without a score = %v, want a not found error", err)
+	}
+}
@@ -0,0 +1,204 @@
+package quaver_test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maskeddd/go-quaver/quaver"
+	"github.com/maskeddd/go-quaver/quavertest"
+)
+
+// seededClient returns a client for a quavertest.Server holding the dataset
+// of seed, along with the transport counting its requests.
+func seededClient(t *testing.T, opts ...quaver.Option) (*quaver.Client, *countingTransport) {
+	t.Helper()
+	srv := quavertest.NewServer()
+	t.Cleanup(srv.Close)
+	seed(srv)
+	return countingClient(srv, opts...)
+}
+
+// countingTransport counts the requests sent to each path.
+type countingTransport struct {
+	base http.RoundTripper
+
+	mu    sync.Mutex
+	paths map[string]int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if t.paths == nil {
+		t.paths = make(map[string]int)
+	}
+	t.paths[strings.TrimPrefix(req.URL.Path, "/v2/")]++
+	t.mu.Unlock()
+	return t.base.RoundTrip(req)
+}
+
+// count returns the number of requests sent to path, relative to the base URL.
+func (t *countingTransport) count(path string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.paths[path]
+}
+
+// total returns the number of requests sent.
+func (t *countingTransport) total() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := 0
+	for _, c := range t.paths {
+		n += c
+	}
+	return n
+}
+
+// countingClient returns a client for srv that counts its requests.
+func countingClient(srv *quavertest.Server, opts ...quaver.Option) (*quaver.Client, *countingTransport) {
+	t := &countingTransport{base: srv.Server.Client().Transport}
+	opts = append([]quaver.Option{quaver.WithHTTPClient(&http.Client{Transport: t})}, opts...)
+	return srv.Client(opts...), t
+}
+
+// epoch is the time the dataset's timestamps count from.
+var epoch = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// Hashes of the maps in the dataset.
+var (
+	md5Ranked4K = hash("ranked 4k")
+	md5Ranked7K = hash("ranked 7k")
+	md5Unranked = hash("unranked")
+)
+
+func hash(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Errors injected into the dataset, by the ID of the map that fails.
+const (
+	mapRateLimited = 429
+	mapServerError = 500
+	mapUnavailable = 503
+)
+
+// seed fills srv with the dataset the service tests run against.
+func seed(srv *quavertest.Server) {
+	users := []*quaver.User{
+		{UserCompact: quaver.UserCompact{ID: 1, Username: "Swan", Country: "US", TimeRegistered: epoch}},
+		{UserCompact: quaver.UserCompact{ID: 2, Username: "Jay", Country: "CA", TimeRegistered: epoch}},
+		{UserCompact: quaver.UserCompact{ID: 3, Username: "Hexa", Country: "US", TimeRegistered: epoch}},
+	}
+	for i, u := range users {
+		u.Statistics4K.OverallPerformanceRating = float64(500 - 100*i)
+		u.Statistics4K.Ranks.Global = i + 1
+		u.Statistics4K.TotalMarvelous = 1000 * (i + 1)
+		u.Statistics7K.OverallPerformanceRating = float64(100 + 100*i)
+		u.Statistics7K.Ranks.Global = 3 - i
+		srv.AddUser(u)
+	}
+
+	srv.AddAchievement(1, &quaver.Achievement{ID: 1, Name: "Combo Breaker", Difficulty: "Easy", IsUnlocked: true})
+	srv.AddBadge(1, &quaver.Badge{ID: 2, Name: "Donator"})
+	srv.AddRank(1, quaver.GameMode4K, &quaver.Rank{Rank: 1, OverallPerformanceRating: 500, Timestamp: epoch})
+	srv.SetTeam(quaver.Team{Developers: []*quaver.User{users[0]}})
+
+	// User 1 has a partial last page of activity, user 2 exactly two pages.
+	for i := range 120 {
+		srv.AddActivity(&quaver.Activity{Id: i + 1, UserID: 1, Type: 1, Timestamp: epoch.Add(time.Duration(i) * time.Minute)})
+	}
+	for i := range 100 {
+		srv.AddActivity(&quaver.Activity{Id: 1000 + i, UserID: 2, Type: 2, Timestamp: epoch.Add(time.Duration(i) * time.Minute)})
+	}
+
+	srv.AddMapset(&quaver.MapsetWithUser{
+		Mapset: quaver.Mapset{
+			MapsetCompact: quaver.MapsetCompact{
+				ID: 10, CreatorID: 1, CreatorUsername: "Swan", Artist: "Camellia", Title: "Ranked Song",
+				Tags: "tech", DateSubmitted: epoch, DateLastUpdated: epoch, IsVisible: true,
+			},
+			Maps: []quaver.Map{
+				{ID: 100, MapsetID: 10, MD5: md5Ranked4K, AlternativeMd5: hash("ranked 4k alt"), GameMode: int(quaver.GameMode4K), RankedStatus: 2, Title: "Ranked Song"},
+				{ID: 101, MapsetID: 10, MD5: md5Ranked7K, GameMode: int(quaver.GameMode7K), RankedStatus: 2, Title: "Ranked Song"},
+			},
+		},
+		User: users[0].UserCompact,
+	})
+	srv.AddMapset(&quaver.MapsetWithUser{
+		Mapset: quaver.Mapset{
+			MapsetCompact: quaver.MapsetCompact{ID: 11, CreatorID: 2, CreatorUsername: "Jay", Artist: "Someone", Title: "Unranked Song"},
+			Maps:          []quaver.Map{{ID: 110, MapsetID: 11, MD5: md5Unranked, GameMode: int(quaver.GameMode4K), RankedStatus: 1}},
+		},
+		User: users[1].UserCompact,
+	})
+	// Enough search results for two pages.
+	for i := range 60 {
+		srv.AddMapset(&quaver.MapsetWithUser{Mapset: quaver.Mapset{
+			MapsetCompact: quaver.MapsetCompact{ID: 200 + i, Artist: "Pack", Title: "Pack Song"},
+			Maps:          []quaver.Map{},
+		}})
+	}
+	srv.AddMapMod(&quaver.MapModeration{Id: 1, MapId: 100, AuthorId: 2, Comment: "Nice map", Status: "Pending", Type: "Suggestion", Timestamp: epoch})
+	srv.AddOffset(&quaver.Offset{ID: 10, Offset: -20})
+
+	score := func(id, userID int, md5 string, pb bool, rating float64, mods quaver.Modifier, grade quaver.Grade) {
+		srv.AddScore(&quaver.ScoreWithUser{
+			Score: quaver.Score{
+				ID: id, UserID: userID, MapMD5: md5, IsPersonalBest: pb, PerformanceRating: rating,
+				Modifiers: int(mods), Grade: string(grade), Timestamp: epoch.Add(time.Duration(id) * time.Hour),
+			},
+			User: users[userID-1].UserCompact,
+		})
+	}
+	score(1, 1, md5Ranked4K, true, 50, quaver.ModifierNone, quaver.GradeS)
+	score(2, 2, md5Ranked4K, true, 45, quaver.ModifierMirror, quaver.GradeA)
+	score(3, 1, md5Ranked4K, false, 40, quaver.ModifierSpeed12X, quaver.GradeA)
+	score(4, 3, md5Ranked4K, true, 60, quaver.ModifierSpeed12X|quaver.ModifierMirror, quaver.GradeX)
+	score(5, 1, md5Ranked7K, true, 30, quaver.ModifierNone, quaver.GradeSS)
+
+	srv.AddClan(&quaver.Clan{ID: 7, OwnerID: 1, Name: "Example Clan", Tag: "EX", CreatedAtJSON: epoch}, 1, 3)
+	for i := range 55 {
+		srv.AddClanActivity(&quaver.ClanActivity{Id: i + 1, ClanId: 7, UserId: 1, Message: "joined", TimestampJSON: epoch})
+	}
+
+	p := &quaver.Playlist{
+		PlaylistCompact: quaver.PlaylistCompact{ID: 3, Name: "Favourites", MapCount: 1, Timestamp: epoch, TimeLastUpdated: epoch},
+		User:            users[0].UserCompact,
+	}
+	p.Mapsets = make([]struct {
+		PlaylistMapsetID int                  `json:"playlist_mapset_id"`
+		Mapset           quaver.MapsetCompact `json:"mapset"`
+		Maps             []struct {
+			PlaylistMapID int        `json:"playlist_map_id"`
+			Map           quaver.Map `json:"map"`
+		} `json:"maps"`
+	}, 1)
+	p.Mapsets[0].PlaylistMapsetID = 1
+	p.Mapsets[0].Mapset.ID = 10
+	p.Mapsets[0].Maps = append(p.Mapsets[0].Maps, struct {
+		PlaylistMapID int        `json:"playlist_map_id"`
+		Map           quaver.Map `json:"map"`
+	}{PlaylistMapID: 1, Map: quaver.Map{ID: 100, MD5: md5Ranked4K}})
+	srv.AddPlaylist(p)
+
+	for i := range 3 {
+		srv.AddGame(&quaver.MultiplayerGame{ID: i + 1, UniqueID: hash("game")[:8], Name: "Game", TimeCreated: epoch})
+	}
+
+	srv.SetServerStats(quaver.ServerStats{OnlineUsers: 12, TotalMapsets: 62, TotalScores: 5, TotalUsers: 3})
+	srv.SetCountryPlayers("US", "2")
+	srv.SetCountryPlayers("CA", "1")
+
+	srv.AddMapFile(100, []byte("ranked 4k"))
+	srv.AddReplayFile(1, []byte("replay\x00\xff"))
+
+	srv.Fail("map/429", http.StatusTooManyRequests, "Too many requests")
+	srv.Fail("map/500", http.StatusInternalServerError, "Internal server error")
+	srv.Fail("map/503", http.StatusServiceUnavailable, "Service unavailable")
+}
@@ -17,31 +17,31 @@ type ServerStats struct {
 type CountryStats map[string]string
 
 // Get returns the total user count, online users, score count, and mapsets on the server.
-func (s *ServerStatsService) Get(ctx context.Context) (*ServerStats, error) {
+func (s *ServerStatsService) Get(ctx context.Context) (*ServerStats, *Response, error) {
 	url := fmt.Sprintf("server/stats")
 
 	var r ServerStats
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return &r, nil
+	return &r, resp, nil
 }
 
 // CountryPlayers returns the amount of players in each country.
-func (s *ServerStatsService) CountryPlayers(ctx context.Context) (*CountryStats, error) {
+func (s *ServerStatsService) CountryPlayers(ctx context.Context) (*CountryStats, *Response, error) {
 	url := fmt.Sprintf("server/stats/country")
 
 	var r struct {
 		Countries *CountryStats `json:"countries"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Countries, nil
+	return r.Countries, resp, nil
 }
@@ -0,0 +1,29 @@
+package quaver_test
+
+import (
+	"testing"
+)
+
+func TestServerStatsGet(t *testing.T) {
+	client, _ := seededClient(t)
+
+	stats, _, err := client.ServerStats.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.OnlineUsers != 12 || stats.TotalUsers != 3 || stats.TotalScores != 5 {
+		t.Errorf("Get = %+v, want 12 online of 3 users with 5 scores", stats)
+	}
+}
+
+func TestServerStatsCountryPlayers(t *testing.T) {
+	client, _ := seededClient(t)
+
+	countries, _, err := client.ServerStats.CountryPlayers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (*countries)["US"] != "2" || (*countries)["CA"] != "1" {
+		t.Errorf("CountryPlayers = %v, want 2 in US and 1 in CA", *countries)
+	}
+}
@@ -129,48 +129,48 @@ const (
 	scoreTypeFirstPlace scoreType = "firstplace"
 )
 
-func (s *UsersService) get(ctx context.Context, input string) (*User, error) {
+func (s *UsersService) get(ctx context.Context, input string) (*User, *Response, error) {
 	url := fmt.Sprintf("user/%v", input)
 
 	var r struct {
 		User User `json:"user"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return &r.User, nil
+	return &r.User, resp, nil
 }
 
-func (s *UsersService) GetByID(ctx context.Context, id int) (*User, error) {
+func (s *UsersService) GetByID(ctx context.Context, id int) (*User, *Response, error) {
 	return s.get(ctx, strconv.Itoa(id))
 }
 
-func (s *UsersService) GetByName(ctx context.Context, username string) (*User, error) {
+func (s *UsersService) GetByName(ctx context.Context, username string) (*User, *Response, error) {
 	return s.get(ctx, username)
 }
 
-func (s *UsersService) ListAchievements(ctx context.Context, userID int) ([]*Achievement, error) {
+func (s *UsersService) ListAchievements(ctx context.Context, userID int) ([]*Achievement, *Response, error) {
 	url := fmt.Sprintf("user/%v/achievements", userID)
 
 	var r struct {
 		Achievements []*Achievement `json:"achievements"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Achievements, nil
+	return r.Achievements, resp, nil
 }
 
-func (s *UsersService) ListActivity(ctx context.Context, userID int, opts *ListOptions) ([]*Activity, error) {
+func (s *UsersService) ListActivity(ctx context.Context, userID int, opts *ListOptions) ([]*Activity, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("user/%v/activity?%v", userID, v.Encode())
@@ -179,63 +179,72 @@ func (s *UsersService) ListActivity(ctx context.Context, userID int, opts *ListO
 		Activities []*Activity `json:"activities"`
 	}
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Activities, nil
+	resp.setPage(opts, len(r.Activities), 0)
+
+	return r.Activities, resp, nil
+}
+
+// ListActivityPager returns a pager over all of a user's activity.
+func (s *UsersService) ListActivityPager(userID int, opts *ListOptions) *Pager[*Activity] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*Activity, *Response, error) {
+		return s.ListActivity(ctx, userID, &ListOptions{Page: page})
+	})
 }
 
-func (s *UsersService) ListBadges(ctx context.Context, userID int) ([]*Badge, error) {
+func (s *UsersService) ListBadges(ctx context.Context, userID int) ([]*Badge, *Response, error) {
 	url := fmt.Sprintf("user/%v/badges", userID)
 
 	var r struct {
 		Badges []*Badge `json:"badges"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Badges, nil
+	return r.Badges, resp, nil
 }
 
-func (s *UsersService) ListMapsets(ctx context.Context, userID int) ([]*Mapset, error) {
+func (s *UsersService) ListMapsets(ctx context.Context, userID int) ([]*Mapset, *Response, error) {
 	url := fmt.Sprintf("user/%v/mapsets", userID)
 
 	var r struct {
 		Mapsets []*Mapset `json:"mapsets"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Mapsets, nil
+	return r.Mapsets, resp, nil
 }
 
-func (s *UsersService) ListPlaylists(ctx context.Context, userID int) ([]*PlaylistCompact, error) {
+func (s *UsersService) ListPlaylists(ctx context.Context, userID int) ([]*PlaylistCompact, *Response, error) {
 	url := fmt.Sprintf("user/%v/playlists", userID)
 
 	var r struct {
 		Playlists []*PlaylistCompact `json:"playlists"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Playlists, nil
+	return r.Playlists, resp, nil
 }
 
-func (s *UsersService) listScores(ctx context.Context, userID int, mode GameMode, scoreType scoreType, opts *ListOptions) ([]*ScoreWithMap, error) {
+func (s *UsersService) listScores(ctx context.Context, userID int, mode GameMode, scoreType scoreType, opts *ListOptions) ([]*ScoreWithMap, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("user/%v/scores/%d/%v?%v", userID, mode, scoreType, v.Encode())
@@ -244,30 +253,53 @@ func (s *UsersService) listScores(ctx context.Context, userID int, mode GameMode
 		Scores []*ScoreWithMap `json:"scores"`
 	}
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Scores, nil
+	resp.setPage(opts, len(r.Scores), userScoresPageSize)
+
+	return r.Scores, resp, nil
 }
 
-func (s *UsersService) ListBestScores(ctx context.Context, userID int, mode GameMode, opts *ListOptions) ([]*ScoreWithMap, error) {
+func (s *UsersService) ListBestScores(ctx context.Context, userID int, mode GameMode, opts *ListOptions) ([]*ScoreWithMap, *Response, error) {
 	return s.listScores(ctx, userID, mode, scoreTypeBest, opts)
 }
 
-func (s *UsersService) ListRecentScores(ctx context.Context, userID int, mode GameMode, opts *ListOptions) ([]*ScoreWithMap, error) {
+// ListBestScoresPager returns a pager over all of a user's best scores.
+func (s *UsersService) ListBestScoresPager(userID int, mode GameMode, opts *ListOptions) *Pager[*ScoreWithMap] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*ScoreWithMap, *Response, error) {
+		return s.ListBestScores(ctx, userID, mode, &ListOptions{Page: page})
+	})
+}
+
+func (s *UsersService) ListRecentScores(ctx context.Context, userID int, mode GameMode, opts *ListOptions) ([]*ScoreWithMap, *Response, error) {
 	return s.listScores(ctx, userID, mode, scoreTypeRecent, opts)
 }
 
-func (s *UsersService) ListFirstPlaceScores(ctx context.Context, userID int, mode GameMode, opts *ListOptions) ([]*ScoreWithMap, error) {
+// ListRecentScoresPager returns a pager over all of a user's recent scores.
+func (s *UsersService) ListRecentScoresPager(userID int, mode GameMode, opts *ListOptions) *Pager[*ScoreWithMap] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*ScoreWithMap, *Response, error) {
+		return s.ListRecentScores(ctx, userID, mode, &ListOptions{Page: page})
+	})
+}
+
+func (s *UsersService) ListFirstPlaceScores(ctx context.Context, userID int, mode GameMode, opts *ListOptions) ([]*ScoreWithMap, *Response, error) {
 	return s.listScores(ctx, userID, mode, scoreTypeFirstPlace, opts)
 }
 
-func (s *UsersService) ListGradeScores(ctx context.Context, userID int, mode GameMode, grade Grade, opts *ListOptions) ([]*ScoreWithMap, error) {
+// ListFirstPlaceScoresPager returns a pager over all of a user's first place scores.
+func (s *UsersService) ListFirstPlaceScoresPager(userID int, mode GameMode, opts *ListOptions) *Pager[*ScoreWithMap] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*ScoreWithMap, *Response, error) {
+		return s.ListFirstPlaceScores(ctx, userID, mode, &ListOptions{Page: page})
+	})
+}
+
+func (s *UsersService) ListGradeScores(ctx context.Context, userID int, mode GameMode, grade Grade, opts *ListOptions) ([]*ScoreWithMap, *Response, error) {
 	v, err := query.Values(opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	url := fmt.Sprintf("user/%v/scores/%d/grades/%v?%v", userID, mode, grade, v.Encode())
@@ -276,55 +308,64 @@ func (s *UsersService) ListGradeScores(ctx context.Context, userID int, mode Gam
 		Scores []*ScoreWithMap `json:"scores"`
 	}
 
-	err = s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Scores, nil
+	resp.setPage(opts, len(r.Scores), userScoresPageSize)
+
+	return r.Scores, resp, nil
+}
+
+// ListGradeScoresPager returns a pager over all of a user's scores with a given grade.
+func (s *UsersService) ListGradeScoresPager(userID int, mode GameMode, grade Grade, opts *ListOptions) *Pager[*ScoreWithMap] {
+	return newPager(opts, func(ctx context.Context, page int) ([]*ScoreWithMap, *Response, error) {
+		return s.ListGradeScores(ctx, userID, mode, grade, &ListOptions{Page: page})
+	})
 }
 
-func (s *UsersService) ListRankStatistics(ctx context.Context, userID int, mode GameMode) ([]*Rank, error) {
+func (s *UsersService) ListRankStatistics(ctx context.Context, userID int, mode GameMode) ([]*Rank, *Response, error) {
 	url := fmt.Sprintf("user/%v/statistics/%d/rank", userID, mode)
 
 	var r struct {
 		Ranks []*Rank `json:"ranks"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Ranks, nil
+	return r.Ranks, resp, nil
 }
 
-func (s *UsersService) Search(ctx context.Context, query string) ([]*User, error) {
+func (s *UsersService) Search(ctx context.Context, query string) ([]*User, *Response, error) {
 	url := fmt.Sprintf("user/search/%v", query)
 
 	var r struct {
 		Users []*User `json:"users"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return r.Users, nil
+	return r.Users, resp, nil
 }
 
-func (s *UsersService) ListTeam(ctx context.Context) (*Team, error) {
+func (s *UsersService) ListTeam(ctx context.Context) (*Team, *Response, error) {
 	url := "user/team/members"
 
 	var r struct {
 		Team Team `json:"team"`
 	}
 
-	err := s.client.get(ctx, url, &r)
+	resp, err := s.client.get(ctx, url, &r)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	return &r.Team, nil
+	return &r.Team, resp, nil
 }
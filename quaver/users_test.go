@@ -0,0 +1,205 @@
+package quaver_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestUsersGet(t *testing.T) {
+	client, _ := seededClient(t)
+
+	user, _, err := client.Users.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != "Swan" || user.Country != "US" || user.Statistics4K.Ranks.Global != 1 {
+		t.Errorf("GetByID = %+v, want Swan from US ranked 1", user.UserCompact)
+	}
+	if !user.TimeRegistered.Equal(epoch) {
+		t.Errorf("TimeRegistered = %v, want %v", user.TimeRegistered, epoch)
+	}
+
+	user, _, err = client.Users.GetByName(ctx, "Jay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 2 {
+		t.Errorf("GetByName ID = %d, want 2", user.ID)
+	}
+
+	_, _, err = client.Users.GetByID(ctx, 404)
+	if !quaver.IsNotFound(err) {
+		t.Errorf("GetByID of a missing user = %v, want a not found error", err)
+	}
+}
+
+func TestUsersList(t *testing.T) {
+	client, _ := seededClient(t)
+
+	achievements, _, err := client.Users.ListAchievements(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(achievements) != 1 || achievements[0].Name != "Combo Breaker" {
+		t.Errorf("ListAchievements = %+v, want Combo Breaker", achievements)
+	}
+
+	badges, _, err := client.Users.ListBadges(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(badges) != 1 || badges[0].Name != "Donator" {
+		t.Errorf("ListBadges = %+v, want Donator", badges)
+	}
+
+	mapsets, _, err := client.Users.ListMapsets(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mapsets) != 1 || mapsets[0].ID != 10 || len(mapsets[0].Maps) != 2 {
+		t.Errorf("ListMapsets = %+v, want mapset 10 with 2 maps", mapsets)
+	}
+
+	playlists, _, err := client.Users.ListPlaylists(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(playlists) != 1 || playlists[0].Name != "Favourites" {
+		t.Errorf("ListPlaylists = %+v, want Favourites", playlists)
+	}
+
+	ranks, _, err := client.Users.ListRankStatistics(ctx, 1, quaver.GameMode4K)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranks) != 1 || ranks[0].Rank != 1 || ranks[0].OverallPerformanceRating != 500 {
+		t.Errorf("ListRankStatistics = %+v, want rank 1 at 500", ranks)
+	}
+
+	users, _, err := client.Users.Search(ctx, "sw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].ID != 1 {
+		t.Errorf("Search = %+v, want user 1", users)
+	}
+
+	team, _, err := client.Users.ListTeam(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(team.Developers) != 1 || team.Developers[0].ID != 1 {
+		t.Errorf("ListTeam developers = %+v, want user 1", team.Developers)
+	}
+}
+
+func TestUsersListActivity(t *testing.T) {
+	client, _ := seededClient(t)
+
+	activity, resp, err := client.Users.ListActivity(ctx, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 50 || activity[0].Id != 1 {
+		t.Errorf("ListActivity returned %d items starting at %d, want 50 starting at 1", len(activity), activity[0].Id)
+	}
+	if resp.NextPage != 1 {
+		t.Errorf("NextPage = %d, want 1", resp.NextPage)
+	}
+
+	activity, resp, err = client.Users.ListActivity(ctx, 1, &quaver.ListOptions{Page: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 20 || activity[0].Id != 101 {
+		t.Errorf("ListActivity page 2 returned %d items starting at %d, want 20 starting at 101", len(activity), activity[0].Id)
+	}
+	if resp.NextPage != 3 {
+		t.Errorf("NextPage = %d, want 3", resp.NextPage)
+	}
+
+	// Activity has no fixed page size, so the empty page after it is the last.
+	activity, resp, err = client.Users.ListActivity(ctx, 1, &quaver.ListOptions{Page: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 0 || resp.NextPage != 0 {
+		t.Errorf("ListActivity page 3 returned %d items and next page %d, want 0 and 0", len(activity), resp.NextPage)
+	}
+}
+
+func TestUsersListScores(t *testing.T) {
+	client, _ := seededClient(t)
+
+	ids := func(scores []*quaver.ScoreWithMap) []int {
+		var ids []int
+		for _, s := range scores {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name  string
+		list  func() ([]*quaver.ScoreWithMap, *quaver.Response, error)
+		pager *quaver.Pager[*quaver.ScoreWithMap]
+		want  []int
+	}{
+		{
+			"best",
+			func() ([]*quaver.ScoreWithMap, *quaver.Response, error) {
+				return client.Users.ListBestScores(ctx, 1, quaver.GameMode4K, nil)
+			},
+			client.Users.ListBestScoresPager(1, quaver.GameMode4K, nil),
+			[]int{1},
+		},
+		{
+			"recent",
+			func() ([]*quaver.ScoreWithMap, *quaver.Response, error) {
+				return client.Users.ListRecentScores(ctx, 1, quaver.GameMode4K, nil)
+			},
+			client.Users.ListRecentScoresPager(1, quaver.GameMode4K, nil),
+			[]int{3, 1},
+		},
+		{
+			"first place",
+			func() ([]*quaver.ScoreWithMap, *quaver.Response, error) {
+				return client.Users.ListFirstPlaceScores(ctx, 3, quaver.GameMode4K, nil)
+			},
+			client.Users.ListFirstPlaceScoresPager(3, quaver.GameMode4K, nil),
+			[]int{4},
+		},
+		{
+			"grade",
+			func() ([]*quaver.ScoreWithMap, *quaver.Response, error) {
+				return client.Users.ListGradeScores(ctx, 1, quaver.GameMode7K, quaver.GradeSS, nil)
+			},
+			client.Users.ListGradeScoresPager(1, quaver.GameMode7K, quaver.GradeSS, nil),
+			[]int{5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores, _, err := tt.list()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(scores); !slices.Equal(got, tt.want) {
+				t.Fatalf("scores = %v, want %v", got, tt.want)
+			}
+			if scores[0].Map.MD5 == "" {
+				t.Error("score has no map")
+			}
+
+			scores, err = tt.pager.All(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(scores); !slices.Equal(got, tt.want) {
+				t.Errorf("pager scores = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
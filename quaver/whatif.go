@@ -0,0 +1,253 @@
+package quaver
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+)
+
+// HypotheticalScore is a score a user has not set, for UsersService.WhatIf.
+type HypotheticalScore struct {
+	// MapMD5 is the MD5 hash of the map the score is set on. Only a user's
+	// best score on each map counts, so a hypothetical score replaces the
+	// user's score on the same map if it rates higher, and is ignored if it
+	// does not.
+	MapMD5            string
+	PerformanceRating float64
+}
+
+// WhatIf is the effect of hypothetical scores on a user's overall rating.
+type WhatIf struct {
+	// Rating is the user's overall rating calculated from their best scores,
+	// and NewRating the rating including the hypothetical scores.
+	Rating    float64
+	NewRating float64
+
+	// Rank is the user's current global rank, and NewRank the rank they
+	// would have with NewRating, assuming no other user's rating changes.
+	Rank    int
+	NewRank int
+
+	// Replaced holds the user's best scores that no longer count because a
+	// hypothetical score on the same map rates higher.
+	Replaced []*ScoreWithMap
+
+	// Displaced holds the user's best scores that move down the weighting,
+	// in order of their current position.
+	Displaced []*DisplacedScore
+}
+
+// RankDelta returns the number of places the user would move up the global
+// leaderboard.
+func (w *WhatIf) RankDelta() int {
+	return w.Rank - w.NewRank
+}
+
+// DisplacedScore is a best score pushed down the weighting by hypothetical
+// scores.
+type DisplacedScore struct {
+	Score *ScoreWithMap
+
+	// Position and NewPosition are the zero-based positions of the score in
+	// the weighting before and after.
+	Position    int
+	NewPosition int
+
+	// Weighted and NewWeighted are the amounts the score adds to the overall
+	// rating before and after.
+	Weighted    float64
+	NewWeighted float64
+}
+
+// WhatIf returns the overall rating and global rank the user would have in
+// mode after setting the given scores. It fetches every page of the user's
+// best scores, and binary searches the global leaderboard pages up to the
+// user's current rank to place the new rating.
+func (s *UsersService) WhatIf(ctx context.Context, userID int, mode GameMode, scores ...HypotheticalScore) (*WhatIf, error) {
+	user, _, err := s.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	best, err := s.ListBestScoresPager(userID, mode, nil).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w := whatIf(best, scores)
+	w.Rank = user.stats(mode).Ranks.Global
+
+	w.NewRank, err = s.rankFor(ctx, userID, mode, w.Rank, w.NewRating)
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// rankFor returns the global rank a user currently at rank would have with
+// rating, which must be no lower than their current rating, so the new rank
+// is on the page of the current one or an earlier page. It binary searches
+// those pages for the first user the rating would pass.
+func (s *UsersService) rankFor(ctx context.Context, userID int, mode GameMode, rank int, rating float64) (int, error) {
+	type page struct {
+		// others holds the users on the page other than the user.
+		others []*User
+		last   bool
+	}
+
+	pages := make(map[int]*page)
+	fetch := func(n int) (*page, error) {
+		if p, ok := pages[n]; ok {
+			return p, nil
+		}
+		lb, resp, err := s.client.Leaderboards.Global(ctx, mode, &ListOptions{Page: n})
+		if err != nil {
+			return nil, err
+		}
+		p := &page{last: resp.NextPage == 0}
+		for _, u := range lb.users() {
+			if u.ID != userID {
+				p.others = append(p.others, u)
+			}
+		}
+		pages[n] = p
+		return p, nil
+	}
+
+	// passes reports whether the new rank is on page n or an earlier page,
+	// because rating reaches its last user or it is the last page.
+	passes := func(n int) (bool, error) {
+		p, err := fetch(n)
+		if err != nil {
+			return false, err
+		}
+		if p.last || len(p.others) == 0 {
+			return true, nil
+		}
+		return p.others[len(p.others)-1].stats(mode).OverallPerformanceRating <= rating, nil
+	}
+
+	lo, hi := 0, 0
+	if rank > 0 {
+		hi = (rank - 1) / leaderboardPageSize
+	}
+	// Unranked users, and ranks the leaderboard has moved on from, search the
+	// pages after the current one.
+	for {
+		ok, err := passes(hi)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			break
+		}
+		lo, hi = hi+1, 2*hi+1
+	}
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		ok, err := passes(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	p, err := fetch(lo)
+	if err != nil {
+		return 0, err
+	}
+	newRank := lo*leaderboardPageSize + 1
+	for _, u := range p.others {
+		if u.stats(mode).OverallPerformanceRating > rating {
+			newRank++
+		}
+	}
+	return newRank, nil
+}
+
+// whatIf calculates the effect of scores on a user with the given best
+// scores.
+func whatIf(best []*ScoreWithMap, scores []HypotheticalScore) *WhatIf {
+	w := &WhatIf{}
+
+	type entry struct {
+		score  *ScoreWithMap
+		rating float64
+	}
+
+	before := make([]entry, len(best))
+	for i, b := range best {
+		before[i] = entry{b, b.PerformanceRating}
+	}
+	byRating := func(a, b entry) int {
+		return cmp.Compare(b.rating, a.rating)
+	}
+	slices.SortStableFunc(before, byRating)
+
+	hypothetical := make(map[string]float64)
+	for _, h := range scores {
+		md5 := strings.ToLower(h.MapMD5)
+		hypothetical[md5] = max(hypothetical[md5], h.PerformanceRating)
+	}
+
+	var after []entry
+	for _, e := range before {
+		md5 := strings.ToLower(e.score.MapMD5)
+		if r, ok := hypothetical[md5]; ok && r > e.rating {
+			w.Replaced = append(w.Replaced, e.score)
+			continue
+		}
+		delete(hypothetical, md5)
+		after = append(after, e)
+	}
+	for _, r := range hypothetical {
+		after = append(after, entry{rating: r})
+	}
+	slices.SortStableFunc(after, byRating)
+
+	positions := make(map[*ScoreWithMap]int)
+	for i, e := range after {
+		w.NewRating += weighted(e.rating, i)
+		if e.score != nil {
+			positions[e.score] = i
+		}
+	}
+	for i, e := range before {
+		w.Rating += weighted(e.rating, i)
+
+		j, ok := positions[e.score]
+		if !ok || j <= i {
+			continue
+		}
+		w.Displaced = append(w.Displaced, &DisplacedScore{
+			Score:       e.score,
+			Position:    i,
+			NewPosition: j,
+			Weighted:    weighted(e.rating, i),
+			NewWeighted: weighted(e.rating, j),
+		})
+	}
+
+	return w
+}
+
+// weighted returns the amount a score rating adds to the overall rating at
+// the given position.
+func weighted(rating float64, position int) float64 {
+	return rating * math.Pow(overallRatingDecay, float64(position))
+}
+
+// stats returns the user's statistics for mode.
+func (u *User) stats(mode GameMode) *Statistics {
+	if mode == GameMode7K {
+		return &u.Statistics7K
+	}
+	return &u.Statistics4K
+}
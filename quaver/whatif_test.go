@@ -0,0 +1,145 @@
+package quaver_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+	"github.com/maskeddd/go-quaver/quavertest"
+)
+
+func TestWhatIf(t *testing.T) {
+	srv := quavertest.NewServer()
+	defer srv.Close()
+
+	// 1000 users rated 1000 down to 1, so user i has rank i.
+	for i := 1; i <= 1000; i++ {
+		u := &quaver.User{UserCompact: quaver.UserCompact{ID: i}}
+		u.Statistics4K.OverallPerformanceRating = float64(1001 - i)
+		u.Statistics4K.Ranks.Global = i
+		srv.AddUser(u)
+	}
+	srv.AddMap(&quaver.Map{ID: 1, MD5: "a", GameMode: int(quaver.GameMode4K)})
+	srv.AddScore(&quaver.ScoreWithUser{Score: quaver.Score{
+		ID: 1, UserID: 800, MapMD5: "a", IsPersonalBest: true, PerformanceRating: 201,
+	}})
+
+	client, transport := countingClient(srv)
+	w, err := whatIf(client, 800, quaver.HypotheticalScore{MapMD5: "b", PerformanceRating: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 300 + 201 * 0.95 = 490.95, passing every user rated 490 or less.
+	if w.Rating != 201 || math.Abs(w.NewRating-490.95) > 1e-9 {
+		t.Errorf("Rating = %v, NewRating = %v, want 201 and 490.95", w.Rating, w.NewRating)
+	}
+	if w.Rank != 800 || w.NewRank != 511 {
+		t.Errorf("Rank = %d, NewRank = %d, want 800 and 511", w.Rank, w.NewRank)
+	}
+
+	// The pages up to rank 800 are 0 to 15, so a binary search needs at most 5.
+	if n := transport.count("leaderboard/global"); n > 5 {
+		t.Errorf("fetched %d leaderboard pages, want at most 5", n)
+	}
+}
+
+func TestWhatIfUnranked(t *testing.T) {
+	srv := quavertest.NewServer()
+	defer srv.Close()
+
+	for i := 1; i <= 120; i++ {
+		u := &quaver.User{UserCompact: quaver.UserCompact{ID: i}}
+		u.Statistics4K.OverallPerformanceRating = float64(1001 - i)
+		u.Statistics4K.Ranks.Global = i
+		srv.AddUser(u)
+	}
+	srv.AddUser(&quaver.User{UserCompact: quaver.UserCompact{ID: 500}})
+
+	client, _ := countingClient(srv)
+	w, err := whatIf(client, 500, quaver.HypotheticalScore{MapMD5: "a", PerformanceRating: 950.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.NewRank != 51 {
+		t.Errorf("NewRank = %d, want 51", w.NewRank)
+	}
+
+	w, err = whatIf(client, 500, quaver.HypotheticalScore{MapMD5: "a", PerformanceRating: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.NewRank != 121 {
+		t.Errorf("NewRank = %d, want 121", w.NewRank)
+	}
+}
+
+func whatIf(client *quaver.Client, userID int, scores ...quaver.HypotheticalScore) (*quaver.WhatIf, error) {
+	return client.Users.WhatIf(ctx, userID, quaver.GameMode4K, scores...)
+}
+
+func TestWhatIfReplacedDisplaced(t *testing.T) {
+	srv := quavertest.NewServer()
+	defer srv.Close()
+
+	u := &quaver.User{UserCompact: quaver.UserCompact{ID: 1}}
+	u.Statistics4K.OverallPerformanceRating = 230.15
+	u.Statistics4K.Ranks.Global = 1
+	srv.AddUser(u)
+	for i, rating := range []float64{100, 80, 60} {
+		md5 := string(rune('a' + i))
+		srv.AddMap(&quaver.Map{ID: i + 1, MD5: md5, GameMode: int(quaver.GameMode4K)})
+		srv.AddScore(&quaver.ScoreWithUser{Score: quaver.Score{
+			ID: i + 1, UserID: 1, MapMD5: md5, IsPersonalBest: true, PerformanceRating: rating,
+		}})
+	}
+
+	client, _ := countingClient(srv)
+	w, err := whatIf(client, 1,
+		// Replaces the score on a, whatever the case of its hash.
+		quaver.HypotheticalScore{MapMD5: "A", PerformanceRating: 120},
+		// Rates lower than the score on b, so it does not count.
+		quaver.HypotheticalScore{MapMD5: "b", PerformanceRating: 70},
+		// Pushes b and c down one place.
+		quaver.HypotheticalScore{MapMD5: "d", PerformanceRating: 90},
+		// Goes below every best score, displacing none.
+		quaver.HypotheticalScore{MapMD5: "e", PerformanceRating: 10},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rating := 100 + 80*0.95 + 60*math.Pow(0.95, 2)
+	newRating := 120 + 90*0.95 + 80*math.Pow(0.95, 2) + 60*math.Pow(0.95, 3) + 10*math.Pow(0.95, 4)
+	if math.Abs(w.Rating-rating) > 1e-9 || math.Abs(w.NewRating-newRating) > 1e-9 {
+		t.Errorf("Rating = %v, NewRating = %v, want %v and %v", w.Rating, w.NewRating, rating, newRating)
+	}
+
+	if len(w.Replaced) != 1 || w.Replaced[0].ID != 1 {
+		t.Errorf("Replaced holds %d scores, want only score 1 on a", len(w.Replaced))
+	}
+
+	want := []struct {
+		id, position, newPosition int
+		rating                    float64
+	}{
+		{2, 1, 2, 80},
+		{3, 2, 3, 60},
+	}
+	if len(w.Displaced) != len(want) {
+		t.Fatalf("Displaced holds %d scores, want %d", len(w.Displaced), len(want))
+	}
+	for i, want := range want {
+		d := w.Displaced[i]
+		if d.Score.ID != want.id || d.Position != want.position || d.NewPosition != want.newPosition {
+			t.Errorf("Displaced[%d] = score %d from %d to %d, want score %d from %d to %d",
+				i, d.Score.ID, d.Position, d.NewPosition, want.id, want.position, want.newPosition)
+		}
+		weighted := want.rating * math.Pow(0.95, float64(want.position))
+		newWeighted := want.rating * math.Pow(0.95, float64(want.newPosition))
+		if math.Abs(d.Weighted-weighted) > 1e-9 || math.Abs(d.NewWeighted-newWeighted) > 1e-9 {
+			t.Errorf("Displaced[%d] weighted %v then %v, want %v then %v",
+				i, d.Weighted, d.NewWeighted, weighted, newWeighted)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package quavertest
+
+import (
+	"sync"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// data is the in-memory dataset served by a Server.
+type data struct {
+	mu sync.RWMutex
+
+	users        []*quaver.User
+	achievements map[int][]*quaver.Achievement
+	activity     map[int][]*quaver.Activity
+	badges       map[int][]*quaver.Badge
+	ranks        map[rankKey][]*quaver.Rank
+	team         quaver.Team
+
+	maps    []*quaver.Map
+	mapMods map[int][]*quaver.MapModeration
+	mapsets []*quaver.MapsetWithUser
+	offsets []*quaver.Offset
+
+	scores []*quaver.ScoreWithUser
+
+	clans        []*quaver.Clan
+	clanActivity map[int][]*quaver.ClanActivity
+	clanMembers  map[int][]int
+
+	playlists []*quaver.Playlist
+	games     []*quaver.MultiplayerGame
+
+	stats        quaver.ServerStats
+	countryStats quaver.CountryStats
+
+	mapFiles    map[int][]byte
+	replayFiles map[int][]byte
+}
+
+type rankKey struct {
+	userID int
+	mode   quaver.GameMode
+}
+
+func newData() *data {
+	return &data{
+		achievements: make(map[int][]*quaver.Achievement),
+		activity:     make(map[int][]*quaver.Activity),
+		badges:       make(map[int][]*quaver.Badge),
+		ranks:        make(map[rankKey][]*quaver.Rank),
+		mapMods:      make(map[int][]*quaver.MapModeration),
+		clanActivity: make(map[int][]*quaver.ClanActivity),
+		clanMembers:  make(map[int][]int),
+		countryStats: make(quaver.CountryStats),
+		mapFiles:     make(map[int][]byte),
+		replayFiles:  make(map[int][]byte),
+	}
+}
+
+// AddUser adds a user to the dataset.
+func (s *Server) AddUser(u *quaver.User) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.users = append(s.data.users, u)
+}
+
+// AddAchievement adds an achievement to a user.
+func (s *Server) AddAchievement(userID int, a *quaver.Achievement) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.achievements[userID] = append(s.data.achievements[userID], a)
+}
+
+// AddActivity adds an activity entry to the user it belongs to.
+func (s *Server) AddActivity(a *quaver.Activity) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.activity[a.UserID] = append(s.data.activity[a.UserID], a)
+}
+
+// AddBadge adds a badge to a user.
+func (s *Server) AddBadge(userID int, b *quaver.Badge) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.badges[userID] = append(s.data.badges[userID], b)
+}
+
+// AddRank adds a rank history entry to a user for the given mode.
+func (s *Server) AddRank(userID int, mode quaver.GameMode, r *quaver.Rank) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	k := rankKey{userID, mode}
+	s.data.ranks[k] = append(s.data.ranks[k], r)
+}
+
+// SetTeam sets the team returned by the team members endpoint.
+func (s *Server) SetTeam(t quaver.Team) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.team = t
+}
+
+// AddMap adds a map to the dataset.
+func (s *Server) AddMap(m *quaver.Map) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.maps = append(s.data.maps, m)
+}
+
+// AddMapMod adds a mod to the map it belongs to.
+func (s *Server) AddMapMod(m *quaver.MapModeration) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.mapMods[m.MapId] = append(s.data.mapMods[m.MapId], m)
+}
+
+// AddMapset adds a mapset to the dataset. Its maps are added as well.
+func (s *Server) AddMapset(m *quaver.MapsetWithUser) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.mapsets = append(s.data.mapsets, m)
+	for i := range m.Maps {
+		s.data.maps = append(s.data.maps, &m.Maps[i])
+	}
+}
+
+// AddOffset adds an online offset to the dataset.
+func (s *Server) AddOffset(o *quaver.Offset) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.offsets = append(s.data.offsets, o)
+}
+
+// AddScore adds a score to the dataset. Scores are matched to maps by MapMD5.
+func (s *Server) AddScore(sc *quaver.ScoreWithUser) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.scores = append(s.data.scores, sc)
+}
+
+// AddClan adds a clan and the IDs of its members to the dataset.
+func (s *Server) AddClan(c *quaver.Clan, memberIDs ...int) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.clans = append(s.data.clans, c)
+	s.data.clanMembers[c.ID] = append(s.data.clanMembers[c.ID], memberIDs...)
+}
+
+// AddClanActivity adds an activity entry to the clan it belongs to.
+func (s *Server) AddClanActivity(a *quaver.ClanActivity) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.clanActivity[a.ClanId] = append(s.data.clanActivity[a.ClanId], a)
+}
+
+// AddPlaylist adds a playlist to the dataset.
+func (s *Server) AddPlaylist(p *quaver.Playlist) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.playlists = append(s.data.playlists, p)
+}
+
+// AddGame adds a multiplayer game to the dataset.
+func (s *Server) AddGame(g *quaver.MultiplayerGame) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.games = append(s.data.games, g)
+}
+
+// SetServerStats sets the server statistics.
+func (s *Server) SetServerStats(stats quaver.ServerStats) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.stats = stats
+}
+
+// SetCountryPlayers sets the number of players in a country.
+func (s *Server) SetCountryPlayers(country, players string) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.countryStats[country] = players
+}
+
+// AddMapFile sets the .qua file served for a map ID.
+func (s *Server) AddMapFile(mapID int, b []byte) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.mapFiles[mapID] = b
+}
+
+// AddReplayFile sets the .qr file served for a replay ID.
+func (s *Server) AddReplayFile(replayID int, b []byte) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	s.data.replayFiles[replayID] = b
+}
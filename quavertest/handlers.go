@@ -0,0 +1,518 @@
+package quavertest
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// paginate returns the items on the page requested by r. The result is never
+// nil, so it encodes as an empty JSON array.
+func paginate[T any](r *http.Request, items []T) []T {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	start := max(page, 0) * pageSize
+	if start >= len(items) {
+		return []T{}
+	}
+	return items[start:min(start+pageSize, len(items))]
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func parseMode(s string) (quaver.GameMode, bool) {
+	i, err := strconv.Atoi(s)
+	if err != nil || (i != int(quaver.GameMode4K) && i != int(quaver.GameMode7K)) {
+		return 0, false
+	}
+	return quaver.GameMode(i), true
+}
+
+func statistics(u *quaver.User, mode quaver.GameMode) *quaver.Statistics {
+	if mode == quaver.GameMode7K {
+		return &u.Statistics7K
+	}
+	return &u.Statistics4K
+}
+
+func (s *Server) findUser(idOrName string) *quaver.User {
+	id, err := strconv.Atoi(idOrName)
+	for _, u := range s.data.users {
+		if (err == nil && u.ID == id) || strings.EqualFold(u.Username, idOrName) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) findMap(idOrMD5 string) *quaver.Map {
+	id, err := strconv.Atoi(idOrMD5)
+	for _, m := range s.data.maps {
+		if (err == nil && m.ID == id) || strings.EqualFold(m.MD5, idOrMD5) || strings.EqualFold(m.AlternativeMd5, idOrMD5) {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *Server) serveUser(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) == 2 && seg[0] == "team" && seg[1] == "members" {
+		writeJSON(w, map[string]any{"team": s.data.team})
+		return
+	}
+	if len(seg) == 2 && seg[0] == "search" {
+		users := []*quaver.User{}
+		for _, u := range s.data.users {
+			if strings.Contains(strings.ToLower(u.Username), strings.ToLower(seg[1])) {
+				users = append(users, u)
+			}
+		}
+		writeJSON(w, map[string]any{"users": users})
+		return
+	}
+	if len(seg) == 0 {
+		writeNotFound(w)
+		return
+	}
+
+	u := s.findUser(seg[0])
+	if u == nil {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	switch {
+	case len(seg) == 1:
+		writeJSON(w, map[string]any{"user": u})
+	case len(seg) == 2 && seg[1] == "achievements":
+		writeJSON(w, map[string]any{"achievements": nonNil(s.data.achievements[u.ID])})
+	case len(seg) == 2 && seg[1] == "activity":
+		writeJSON(w, map[string]any{"activities": paginate(r, s.data.activity[u.ID])})
+	case len(seg) == 2 && seg[1] == "badges":
+		writeJSON(w, map[string]any{"badges": nonNil(s.data.badges[u.ID])})
+	case len(seg) == 2 && seg[1] == "mapsets":
+		mapsets := []*quaver.Mapset{}
+		for _, m := range s.data.mapsets {
+			if m.CreatorID == u.ID {
+				mapsets = append(mapsets, &m.Mapset)
+			}
+		}
+		writeJSON(w, map[string]any{"mapsets": mapsets})
+	case len(seg) == 2 && seg[1] == "playlists":
+		playlists := []*quaver.PlaylistCompact{}
+		for _, p := range s.data.playlists {
+			if p.User.ID == u.ID {
+				playlists = append(playlists, &p.PlaylistCompact)
+			}
+		}
+		writeJSON(w, map[string]any{"playlists": playlists})
+	case len(seg) >= 4 && seg[1] == "scores":
+		s.serveUserScores(w, r, u, seg[2:])
+	case len(seg) == 4 && seg[1] == "statistics" && seg[3] == "rank":
+		mode, ok := parseMode(seg[2])
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Invalid mode")
+			return
+		}
+		writeJSON(w, map[string]any{"ranks": nonNil(s.data.ranks[rankKey{u.ID, mode}])})
+	default:
+		writeNotFound(w)
+	}
+}
+
+func (s *Server) serveUserScores(w http.ResponseWriter, r *http.Request, u *quaver.User, seg []string) {
+	mode, ok := parseMode(seg[0])
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Invalid mode")
+		return
+	}
+
+	var scores []*quaver.ScoreWithMap
+	for _, sc := range s.data.scores {
+		if sc.UserID != u.ID {
+			continue
+		}
+		m := s.findMap(sc.MapMD5)
+		if m == nil || m.GameMode != int(mode) {
+			continue
+		}
+		scores = append(scores, &quaver.ScoreWithMap{Score: sc.Score, Map: *m})
+	}
+
+	switch {
+	case len(seg) == 2 && seg[1] == "best":
+		scores = slices.DeleteFunc(scores, func(sc *quaver.ScoreWithMap) bool { return !sc.IsPersonalBest })
+		slices.SortStableFunc(scores, func(a, b *quaver.ScoreWithMap) int {
+			return cmp.Compare(b.PerformanceRating, a.PerformanceRating)
+		})
+	case len(seg) == 2 && seg[1] == "recent":
+		slices.SortStableFunc(scores, func(a, b *quaver.ScoreWithMap) int {
+			return b.Timestamp.Compare(a.Timestamp)
+		})
+	case len(seg) == 2 && seg[1] == "firstplace":
+		scores = slices.DeleteFunc(scores, func(sc *quaver.ScoreWithMap) bool {
+			top := s.mapScores(sc.MapMD5, nil)
+			return len(top) == 0 || top[0].ID != sc.ID
+		})
+	case len(seg) == 3 && seg[1] == "grades":
+		scores = slices.DeleteFunc(scores, func(sc *quaver.ScoreWithMap) bool {
+			return !sc.IsPersonalBest || sc.Grade != seg[2]
+		})
+	default:
+		writeNotFound(w)
+		return
+	}
+
+	writeJSON(w, map[string]any{"scores": paginate(r, scores)})
+}
+
+func (s *Server) serveMap(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) == 0 || len(seg) > 2 || (len(seg) == 2 && seg[1] != "mods") {
+		writeNotFound(w)
+		return
+	}
+
+	m := s.findMap(seg[0])
+	if m == nil {
+		writeError(w, http.StatusNotFound, "Map not found")
+		return
+	}
+
+	if len(seg) == 2 {
+		writeJSON(w, map[string]any{"mods": nonNil(s.data.mapMods[m.ID])})
+		return
+	}
+	writeJSON(w, map[string]any{"map": m})
+}
+
+func (s *Server) serveMapset(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) != 1 {
+		writeNotFound(w)
+		return
+	}
+
+	switch seg[0] {
+	case "ranked":
+		ids := []int{}
+		for _, m := range s.data.mapsets {
+			if slices.ContainsFunc(m.Maps, func(m quaver.Map) bool { return m.RankedStatus == 2 }) {
+				ids = append(ids, m.ID)
+			}
+		}
+		writeJSON(w, map[string]any{"ranked_mapsets": ids})
+	case "offsets":
+		writeJSON(w, map[string]any{"online_offsets": nonNil(s.data.offsets)})
+	case "search":
+		q := strings.ToLower(r.URL.Query().Get("search"))
+		var mapsets []*quaver.Mapset
+		for _, m := range s.data.mapsets {
+			text := strings.ToLower(m.Artist + " " + m.Title + " " + m.CreatorUsername + " " + m.Tags)
+			if strings.Contains(text, q) {
+				mapsets = append(mapsets, &m.Mapset)
+			}
+		}
+		writeJSON(w, map[string]any{"mapsets": paginate(r, mapsets)})
+	default:
+		id, _ := strconv.Atoi(seg[0])
+		for _, m := range s.data.mapsets {
+			if m.ID == id {
+				writeJSON(w, map[string]any{"mapset": m})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Mapset not found")
+	}
+}
+
+// mapScores returns the personal best scores on a map accepted by keep,
+// ordered by performance rating.
+func (s *Server) mapScores(md5 string, keep func(*quaver.ScoreWithUser) bool) []*quaver.ScoreWithUser {
+	scores := []*quaver.ScoreWithUser{}
+	for _, sc := range s.data.scores {
+		if strings.EqualFold(sc.MapMD5, md5) && sc.IsPersonalBest && (keep == nil || keep(sc)) {
+			scores = append(scores, sc)
+		}
+	}
+	slices.SortStableFunc(scores, func(a, b *quaver.ScoreWithUser) int {
+		return cmp.Compare(b.PerformanceRating, a.PerformanceRating)
+	})
+	return scores[:min(len(scores), 50)]
+}
+
+func withMods(mods quaver.Modifier) func(*quaver.ScoreWithUser) bool {
+	return func(sc *quaver.ScoreWithUser) bool {
+		return quaver.Modifier(sc.Modifiers) == mods
+	}
+}
+
+func withRate(mods quaver.Modifier) func(*quaver.ScoreWithUser) bool {
+	return func(sc *quaver.ScoreWithUser) bool {
+		return quaver.Modifier(sc.Modifiers)&quaver.RateModifiers == mods&quaver.RateModifiers
+	}
+}
+
+func (s *Server) serveScores(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) < 2 {
+		writeNotFound(w)
+		return
+	}
+	md5 := seg[0]
+
+	parseMods := func(v string) (quaver.Modifier, bool) {
+		m, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid modifiers")
+			return 0, false
+		}
+		return quaver.Modifier(m), true
+	}
+
+	switch {
+	case len(seg) == 2 && seg[1] == "global":
+		writeJSON(w, map[string]any{"scores": s.mapScores(md5, nil)})
+	case len(seg) == 3 && seg[1] == "mods":
+		if mods, ok := parseMods(seg[2]); ok {
+			writeJSON(w, map[string]any{"scores": s.mapScores(md5, withMods(mods))})
+		}
+	case len(seg) == 3 && seg[1] == "rate":
+		if mods, ok := parseMods(seg[2]); ok {
+			writeJSON(w, map[string]any{"scores": s.mapScores(md5, withRate(mods))})
+		}
+	case len(seg) == 3 || (len(seg) == 4 && seg[2] == "mods"):
+		userID, err := strconv.Atoi(seg[1])
+		if err != nil {
+			writeNotFound(w)
+			return
+		}
+
+		var best *quaver.ScoreWithUser
+		for _, sc := range s.data.scores {
+			if !strings.EqualFold(sc.MapMD5, md5) || sc.UserID != userID {
+				continue
+			}
+			switch {
+			case len(seg) == 4:
+				mods, ok := parseMods(seg[3])
+				if !ok {
+					return
+				}
+				if !withMods(mods)(sc) {
+					continue
+				}
+			case seg[2] == "global":
+				if !sc.IsPersonalBest {
+					continue
+				}
+			case seg[2] != "all":
+				writeNotFound(w)
+				return
+			}
+			if best == nil || sc.PerformanceRating > best.PerformanceRating {
+				best = sc
+			}
+		}
+
+		if best == nil {
+			writeError(w, http.StatusNotFound, "Score not found")
+			return
+		}
+		writeJSON(w, map[string]any{"score": best})
+	default:
+		writeNotFound(w)
+	}
+}
+
+func totalHits(u *quaver.User) int {
+	hits := 0
+	for _, st := range []*quaver.Statistics{&u.Statistics4K, &u.Statistics7K} {
+		hits += st.TotalMarvelous + st.TotalPerfect + st.TotalGreat + st.TotalGood + st.TotalOkay
+	}
+	return hits
+}
+
+func (s *Server) serveLeaderboard(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) != 1 {
+		writeNotFound(w)
+		return
+	}
+
+	q := r.URL.Query()
+	users := slices.Clone(s.data.users)
+
+	switch seg[0] {
+	case "global", "country":
+		mode, ok := parseMode(q.Get("mode"))
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Invalid mode")
+			return
+		}
+		if seg[0] == "country" {
+			country := q.Get("country")
+			users = slices.DeleteFunc(users, func(u *quaver.User) bool { return !strings.EqualFold(u.Country, country) })
+		}
+		slices.SortStableFunc(users, func(a, b *quaver.User) int {
+			return cmp.Compare(statistics(b, mode).OverallPerformanceRating, statistics(a, mode).OverallPerformanceRating)
+		})
+	case "hits":
+		slices.SortStableFunc(users, func(a, b *quaver.User) int {
+			return cmp.Compare(totalHits(b), totalHits(a))
+		})
+	default:
+		writeNotFound(w)
+		return
+	}
+
+	page := paginate(r, users)
+	lb := quaver.Leaderboard{TotalUsers: len(users), Users: make([]quaver.User, len(page))}
+	for i, u := range page {
+		lb.Users[i] = *u
+	}
+	writeJSON(w, lb)
+}
+
+func (s *Server) serveClan(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) == 0 || len(seg) > 2 {
+		writeNotFound(w)
+		return
+	}
+
+	id, _ := strconv.Atoi(seg[0])
+	i := slices.IndexFunc(s.data.clans, func(c *quaver.Clan) bool { return c.ID == id })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Clan not found")
+		return
+	}
+	clan := s.data.clans[i]
+
+	switch {
+	case len(seg) == 1:
+		writeJSON(w, map[string]any{"clan": clan})
+	case seg[1] == "activity":
+		writeJSON(w, map[string]any{"activity": paginate(r, s.data.clanActivity[clan.ID])})
+	case seg[1] == "members":
+		members := []*quaver.User{}
+		for _, id := range s.data.clanMembers[clan.ID] {
+			if u := s.findUser(strconv.Itoa(id)); u != nil {
+				members = append(members, u)
+			}
+		}
+		writeJSON(w, map[string]any{"members": members})
+	default:
+		writeNotFound(w)
+	}
+}
+
+func (s *Server) servePlaylists(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) == 1 && seg[0] == "search" {
+		q := strings.ToLower(r.URL.Query().Get("query"))
+		var playlists []*quaver.Playlist
+		for _, p := range s.data.playlists {
+			if strings.Contains(strings.ToLower(p.Name), q) {
+				playlists = append(playlists, p)
+			}
+		}
+		writeJSON(w, map[string]any{"playlists": paginate(r, playlists)})
+		return
+	}
+	if len(seg) != 1 && !(len(seg) == 3 && seg[1] == "contains") {
+		writeNotFound(w)
+		return
+	}
+
+	id, _ := strconv.Atoi(seg[0])
+	i := slices.IndexFunc(s.data.playlists, func(p *quaver.Playlist) bool { return p.ID == id })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Playlist not found")
+		return
+	}
+	p := s.data.playlists[i]
+
+	if len(seg) == 1 {
+		writeJSON(w, map[string]any{"playlist": p})
+		return
+	}
+
+	mapID, _ := strconv.Atoi(seg[2])
+	exists := false
+	for _, ms := range p.Mapsets {
+		for _, m := range ms.Maps {
+			exists = exists || m.Map.ID == mapID
+		}
+	}
+	writeJSON(w, map[string]any{"exists": exists})
+}
+
+func (s *Server) serveMultiplayer(w http.ResponseWriter, r *http.Request, seg []string) {
+	switch {
+	case len(seg) == 1 && seg[0] == "games":
+		games := make([]*quaver.MultiplayerGameCompact, len(s.data.games))
+		for i, g := range s.data.games {
+			c := &quaver.MultiplayerGameCompact{
+				ID:          g.ID,
+				UniqueID:    g.UniqueID,
+				Name:        g.Name,
+				TimeCreated: g.TimeCreated,
+				Matches:     []*quaver.MultiplayerGameMatch{},
+			}
+			for _, m := range g.Matches {
+				c.Matches = append(c.Matches, &m.MultiplayerGameMatch)
+			}
+			games[i] = c
+		}
+		writeJSON(w, map[string]any{"games": paginate(r, games)})
+	case len(seg) == 2 && seg[0] == "game":
+		id, _ := strconv.Atoi(seg[1])
+		for _, g := range s.data.games {
+			if g.ID == id {
+				writeJSON(w, map[string]any{"game": g})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Game not found")
+	default:
+		writeNotFound(w)
+	}
+}
+
+func (s *Server) serveServerStats(w http.ResponseWriter, r *http.Request, seg []string) {
+	switch {
+	case len(seg) == 1 && seg[0] == "stats":
+		writeJSON(w, s.data.stats)
+	case len(seg) == 2 && seg[0] == "stats" && seg[1] == "country":
+		writeJSON(w, map[string]any{"countries": s.data.countryStats})
+	default:
+		writeNotFound(w)
+	}
+}
+
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) != 2 {
+		writeNotFound(w)
+		return
+	}
+
+	id, _ := strconv.Atoi(seg[1])
+	var b []byte
+	var ok bool
+	switch seg[0] {
+	case "map":
+		b, ok = s.data.mapFiles[id]
+	case "replay":
+		b, ok = s.data.replayFiles[id]
+	}
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(b)
+}
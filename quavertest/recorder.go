@@ -0,0 +1,260 @@
+package quavertest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode controls whether a Recorder talks to the network.
+type Mode int
+
+const (
+	// ModeReplay serves responses from fixtures only, failing requests that
+	// have none.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends every request and overwrites its fixture.
+	ModeRecord
+
+	// ModeRecordMissing serves existing fixtures and records the rest.
+	ModeRecordMissing
+)
+
+// Scrubber rewrites a response body before it is written to a fixture.
+type Scrubber func(body []byte) []byte
+
+// Recorder is an http.RoundTripper that records responses to fixture files
+// and replays them offline. Fixtures are keyed by method, path and query, so
+// they can be replayed against any base URL.
+//
+//	rec := quavertest.NewRecorder("testdata/fixtures", quavertest.ModeReplay)
+//	client, _ := quaver.NewClient(quaver.WithHTTPClient(&http.Client{Transport: rec}))
+type Recorder struct {
+	// Dir is the directory fixtures are stored in.
+	Dir string
+
+	// Mode controls whether requests are sent or replayed.
+	Mode Mode
+
+	// Transport sends requests while recording. If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// Scrubbers are applied in order to response bodies before they are
+	// recorded.
+	Scrubbers []Scrubber
+
+	mu sync.Mutex
+}
+
+// NewRecorder returns a Recorder storing fixtures in dir that scrubs the
+// personal fields of users with ScrubPersonalFields.
+func NewRecorder(dir string, mode Mode) *Recorder {
+	return &Recorder{
+		Dir:       dir,
+		Mode:      mode,
+		Scrubbers: []Scrubber{ScrubPersonalFields},
+	}
+}
+
+// fixture is the format of a recorded response on disk. Bodies that are not
+// valid UTF-8, such as downloaded replays, are stored base64 encoded.
+type fixture struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+	BodyBytes  []byte      `json:"body_bytes,omitempty"`
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fixturePath returns the file a request's fixture is stored in.
+func (r *Recorder) fixturePath(req *http.Request) string {
+	key := req.Method + " " + req.URL.RequestURI()
+	sum := sha256.Sum256([]byte(key))
+
+	name := unsafeChars.ReplaceAllString(strings.Trim(req.URL.Path, "/"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return filepath.Join(r.Dir, fmt.Sprintf("%v_%v_%v.json", req.Method, name, hex.EncodeToString(sum[:6])))
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := r.fixturePath(req)
+
+	if r.Mode != ModeRecord {
+		f, err := readFixture(path)
+		if err == nil {
+			return f.response(req), nil
+		}
+		if r.Mode == ModeReplay || !os.IsNotExist(err) {
+			return nil, fmt.Errorf("quavertest: no fixture for %v %v: %w", req.Method, req.URL.RequestURI(), err)
+		}
+	}
+
+	return r.record(req, path)
+}
+
+func (r *Recorder) record(req *http.Request, path string) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	for _, scrub := range r.Scrubbers {
+		body = scrub(body)
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	header.Del("Date")
+
+	f := &fixture{
+		Method:     req.Method,
+		URL:        req.URL.RequestURI(),
+		StatusCode: resp.StatusCode,
+		Header:     header,
+	}
+	if utf8.Valid(body) {
+		f.Body = string(body)
+	} else {
+		f.BodyBytes = body
+	}
+
+	r.mu.Lock()
+	err = writeFixture(path, f)
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.response(req), nil
+}
+
+func readFixture(path string) (*fixture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f fixture
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func writeFixture(path string, f *fixture) error {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+func (f *fixture) response(req *http.Request) *http.Response {
+	body := f.BodyBytes
+	if body == nil {
+		body = []byte(f.Body)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %v", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// ScrubPersonalFields removes the Steam and Discord IDs of users from JSON
+// responses.
+func ScrubPersonalFields(body []byte) []byte {
+	return ScrubJSONFields("steam_id", "discord_id")(body)
+}
+
+// ScrubJSONFields returns a Scrubber that clears the given fields wherever
+// they appear in a JSON response. String values are replaced with an empty
+// string and all other values with null. Bodies that are not JSON are
+// returned unchanged.
+func ScrubJSONFields(fields ...string) Scrubber {
+	return func(body []byte) []byte {
+		d := json.NewDecoder(bytes.NewReader(body))
+		d.UseNumber()
+
+		var v any
+		if d.Decode(&v) != nil {
+			return body
+		}
+
+		if !scrubValue(v, fields) {
+			return body
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return b
+	}
+}
+
+// scrubValue clears fields in v and reports whether anything changed.
+func scrubValue(v any, fields []string) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if slices.Contains(fields, k) {
+				if _, ok := child.(string); ok {
+					v[k] = ""
+				} else {
+					v[k] = nil
+				}
+				changed = true
+				continue
+			}
+			changed = scrubValue(child, fields) || changed
+		}
+	case []any:
+		for _, child := range v {
+			changed = scrubValue(child, fields) || changed
+		}
+	}
+	return changed
+}
@@ -0,0 +1,218 @@
+package quavertest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// failingTransport fails every request, standing in for a network that is
+// not available while replaying.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network not available")
+}
+
+// get sends a GET request for url through rt and returns the status and body.
+func get(t *testing.T, rt http.RoundTripper, url string) (int, string, error) {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(b), nil
+}
+
+func TestRecorder(t *testing.T) {
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.Add(1)
+		w.Header().Set("Set-Cookie", "session=1")
+		switch r.URL.RequestURI() {
+		case "/v2/user/1?page=1":
+			_, _ = w.Write([]byte(`{"page": 1}`))
+		case "/v2/download/replay/1":
+			_, _ = w.Write([]byte("replay\x00\xff"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec := NewRecorder(dir, ModeRecord)
+	rec.Transport = srv.Client().Transport
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/v2/user/1?page=1", http.StatusOK, `{"page": 1}`},
+		{"/v2/user/1", http.StatusNotFound, `{"error": "Not found"}`},
+		{"/v2/download/replay/1", http.StatusOK, "replay\x00\xff"},
+	}
+	for _, tt := range tests {
+		if _, _, err := get(t, rec, srv.URL+tt.path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := n.Load(); got != 3 {
+		t.Fatalf("recorded %d requests, want 3", got)
+	}
+
+	// Fixtures are keyed by method, path and query, not by host, so they
+	// replay against any base URL without the network.
+	rec = NewRecorder(dir, ModeReplay)
+	rec.Transport = failingTransport{}
+	for _, tt := range tests {
+		status, body, err := get(t, rec, "https://api.quavergame.com"+tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != tt.status || body != tt.body {
+			t.Errorf("%v replayed %d %q, want %d %q", tt.path, status, body, tt.status, tt.body)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/v2/user/1?page=1", nil)
+	f, err := readFixture(rec.fixturePath(req))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Header.Get("Set-Cookie") != "" {
+		t.Error("Set-Cookie header was recorded")
+	}
+}
+
+func TestRecorderReplayMissing(t *testing.T) {
+	rec := NewRecorder(t.TempDir(), ModeReplay)
+	rec.Transport = failingTransport{}
+
+	_, _, err := get(t, rec, "https://api.quavergame.com/v2/user/1")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v, want a missing fixture error", err)
+	}
+}
+
+func TestRecorderRecordMissing(t *testing.T) {
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.Add(1)
+		_, _ = w.Write([]byte(`{"online_users": 1}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec := NewRecorder(dir, ModeRecordMissing)
+	rec.Transport = srv.Client().Transport
+
+	for range 2 {
+		_, body, err := get(t, rec, srv.URL+"/v2/server/stats")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != `{"online_users": 1}` {
+			t.Errorf("body = %q", body)
+		}
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("wrote %d fixtures, want 1", len(files))
+	}
+}
+
+func TestScrubPersonalFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			"user",
+			`{"user": {"id": 1, "steam_id": "76561198000000000", "discord_id": "123456789"}}`,
+			`{"user":{"discord_id":"","id":1,"steam_id":""}}`,
+		},
+		{
+			"nested and numeric",
+			`{"users": [{"id": 1, "steam_id": 7656119, "discord_id": null, "clan": {"discord_id": 5}}]}`,
+			`{"users":[{"clan":{"discord_id":null},"discord_id":null,"id":1,"steam_id":null}]}`,
+		},
+		{
+			"large numbers",
+			`{"id": 12345678901234567890, "steam_id": "1"}`,
+			`{"id":12345678901234567890,"steam_id":""}`,
+		},
+		{"no personal fields", `{"id": 1, "username": "Swan"}`, `{"id": 1, "username": "Swan"}`},
+		{"not JSON", "replay\x00\xff", "replay\x00\xff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(ScrubPersonalFields([]byte(tt.body))); got != tt.want {
+				t.Errorf("ScrubPersonalFields = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrubJSONFields(t *testing.T) {
+	scrub := ScrubJSONFields("email", "ip")
+	got := string(scrub([]byte(`{"email": "a@b.c", "ip": ["127.0.0.1"], "steam_id": "1"}`)))
+	if want := `{"email":"","ip":null,"steam_id":"1"}`; got != want {
+		t.Errorf("ScrubJSONFields = %s, want %s", got, want)
+	}
+}
+
+func TestRecorderScrubs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"user": {"id": 1, "steam_id": "76561198000000000", "discord_id": "123456789"}}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec := NewRecorder(dir, ModeRecord)
+	rec.Transport = srv.Client().Transport
+	_, body, err := get(t, rec, srv.URL+"/v2/user/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither the response nor the fixture on disk holds the IDs.
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("fixtures = %v, %v, want one", files, err)
+	}
+	fixture, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"76561198000000000", "123456789"} {
+		if strings.Contains(body, id) || strings.Contains(string(fixture), id) {
+			t.Errorf("%v was recorded", id)
+		}
+	}
+}
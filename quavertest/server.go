@@ -0,0 +1,152 @@
+// Package quavertest provides an in-process fake of the Quaver v2 API for
+// testing code built on the quaver package.
+//
+//	srv := quavertest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddUser(&quaver.User{UserCompact: quaver.UserCompact{ID: 1, Username: "Swan"}})
+//
+//	client := srv.Client()
+//	user, _, err := client.Users.GetByID(ctx, 1)
+package quavertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// pageSize is the number of items returned per page by paginated endpoints.
+const pageSize = 50
+
+// Server is a fake Quaver API backed by an in-memory dataset.
+type Server struct {
+	*httptest.Server
+
+	data *data
+
+	failMu   sync.Mutex
+	failures map[string]failure
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+// NewServer starts and returns a new Server with an empty dataset. The caller
+// should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		data:     newData(),
+		failures: make(map[string]failure),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BaseURL returns the base URL of the fake API, for use with quaver.WithBaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + "/v2/"
+}
+
+// Client returns a quaver.Client that talks to the server. Retrying is
+// disabled so that injected failures are returned directly; opts are applied
+// after the defaults and may override them.
+func (s *Server) Client(opts ...quaver.Option) *quaver.Client {
+	opts = append([]quaver.Option{
+		quaver.WithBaseURL(s.BaseURL()),
+		quaver.WithHTTPClient(s.Server.Client()),
+		quaver.WithRetry(nil),
+	}, opts...)
+
+	c, err := quaver.NewClient(opts...)
+	if err != nil {
+		panic("quavertest: " + err.Error())
+	}
+	return c
+}
+
+// Fail makes requests to path, relative to the base URL and without the
+// query string, respond with status and an API error message. A status of
+// zero removes the failure.
+func (s *Server) Fail(path string, status int, message string) {
+	s.failMu.Lock()
+	defer s.failMu.Unlock()
+
+	if status == 0 {
+		delete(s.failures, path)
+		return
+	}
+	s.failures[path] = failure{status, message}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/v2/")
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.failMu.Lock()
+	f, failed := s.failures[path]
+	s.failMu.Unlock()
+	if failed {
+		writeError(w, f.status, f.message)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	s.data.mu.RLock()
+	defer s.data.mu.RUnlock()
+
+	seg := strings.Split(path, "/")
+	switch seg[0] {
+	case "user":
+		s.serveUser(w, r, seg[1:])
+	case "map":
+		s.serveMap(w, r, seg[1:])
+	case "mapset":
+		s.serveMapset(w, r, seg[1:])
+	case "scores":
+		s.serveScores(w, r, seg[1:])
+	case "leaderboard":
+		s.serveLeaderboard(w, r, seg[1:])
+	case "clan":
+		s.serveClan(w, r, seg[1:])
+	case "playlists":
+		s.servePlaylists(w, r, seg[1:])
+	case "multiplayer":
+		s.serveMultiplayer(w, r, seg[1:])
+	case "server":
+		s.serveServerStats(w, r, seg[1:])
+	case "download":
+		s.serveDownload(w, r, seg[1:])
+	default:
+		writeNotFound(w)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the format used by the Quaver API.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(quaver.Error{Error: message})
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "Not Found")
+}
@@ -0,0 +1,203 @@
+package replay
+
+import (
+	"math"
+	"slices"
+)
+
+// Analysis is the timing of a simulated replay, in real milliseconds.
+type Analysis struct {
+	// Inputs holds every judged press and release, in the order of
+	// Result.Hits.
+	Inputs []Input
+
+	// Press is the timing of presses.
+	Press Timing
+	// Release is the timing of long note releases.
+	Release Timing
+
+	// Lanes holds the judgements and press timing of each lane, in order.
+	Lanes []Lane
+}
+
+// Input is a judged press or release.
+type Input struct {
+	Object    int
+	Lane      int
+	Release   bool
+	Time      int
+	Offset    float64
+	Judgement Judgement
+}
+
+// Timing summarizes a set of offsets. Negative offsets are early.
+type Timing struct {
+	Count int
+	Early int
+	Late  int
+
+	Mean   float64
+	StdDev float64
+	// UnstableRate is ten times the standard deviation.
+	UnstableRate float64
+
+	Min float64
+	Max float64
+}
+
+// Lane is the judgements and press timing of one lane.
+type Lane struct {
+	Lane           int
+	CountMarvelous int
+	CountPerfect   int
+	CountGreat     int
+	CountGood      int
+	CountOkay      int
+	CountMiss      int
+	Accuracy       float64
+	Timing         Timing
+}
+
+// Bucket is a range of offsets in a histogram.
+type Bucket struct {
+	// Start is the inclusive start of the range and End its exclusive end.
+	Start, End float64
+
+	Count int
+}
+
+// Analyze returns the timing of every press and release in r. Offsets are
+// converted from map time to real time using r.Rate.
+func (r *Result) Analyze() *Analysis {
+	a := &Analysis{}
+
+	rate := r.Rate
+	if rate == 0 {
+		rate = 1
+	}
+
+	var press, release []float64
+	lanes := map[int]*laneAnalysis{}
+	for _, h := range r.Hits {
+		l := lanes[h.Lane]
+		if l == nil {
+			l = &laneAnalysis{}
+			lanes[h.Lane] = l
+		}
+		l.counts[h.Judgement]++
+
+		if !h.Input {
+			continue
+		}
+
+		offset := float64(h.Offset) / rate
+		a.Inputs = append(a.Inputs, Input{
+			Object:    h.Object,
+			Lane:      h.Lane,
+			Release:   h.Release,
+			Time:      h.Time,
+			Offset:    offset,
+			Judgement: h.Judgement,
+		})
+		if h.Release {
+			release = append(release, offset)
+		} else {
+			press = append(press, offset)
+			l.offsets = append(l.offsets, offset)
+		}
+	}
+
+	a.Press = timing(press)
+	a.Release = timing(release)
+
+	keys := make([]int, 0, len(lanes))
+	for k := range lanes {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		l := lanes[k]
+		a.Lanes = append(a.Lanes, Lane{
+			Lane:           k,
+			CountMarvelous: l.counts[JudgementMarvelous],
+			CountPerfect:   l.counts[JudgementPerfect],
+			CountGreat:     l.counts[JudgementGreat],
+			CountGood:      l.counts[JudgementGood],
+			CountOkay:      l.counts[JudgementOkay],
+			CountMiss:      l.counts[JudgementMiss],
+			Accuracy:       judgements(l.counts).Accuracy(),
+			Timing:         timing(l.offsets),
+		})
+	}
+
+	return a
+}
+
+type laneAnalysis struct {
+	counts  [len(judgementWindows)]int
+	offsets []float64
+}
+
+// Histogram counts the offsets of inputs in buckets of size milliseconds,
+// with a bucket starting at zero. Only inputs matching release are counted.
+// Buckets between the first and last filled bucket are included even when
+// empty.
+func (a *Analysis) Histogram(size float64, release bool) []Bucket {
+	if size <= 0 {
+		return nil
+	}
+
+	counts := map[int]int{}
+	lo, hi := math.MaxInt, math.MinInt
+	for _, in := range a.Inputs {
+		if in.Release != release {
+			continue
+		}
+		i := int(math.Floor(in.Offset / size))
+		counts[i]++
+		lo, hi = min(lo, i), max(hi, i)
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	buckets := make([]Bucket, 0, hi-lo+1)
+	for i := lo; i <= hi; i++ {
+		buckets = append(buckets, Bucket{
+			Start: float64(i) * size,
+			End:   float64(i+1) * size,
+			Count: counts[i],
+		})
+	}
+	return buckets
+}
+
+func timing(offsets []float64) Timing {
+	t := Timing{Count: len(offsets)}
+	if len(offsets) == 0 {
+		return t
+	}
+
+	t.Min, t.Max = offsets[0], offsets[0]
+	var sum float64
+	for _, o := range offsets {
+		sum += o
+		t.Min, t.Max = min(t.Min, o), max(t.Max, o)
+		switch {
+		case o < 0:
+			t.Early++
+		case o > 0:
+			t.Late++
+		}
+	}
+	t.Mean = sum / float64(len(offsets))
+
+	var sq float64
+	for _, o := range offsets {
+		sq += (o - t.Mean) * (o - t.Mean)
+	}
+	t.StdDev = math.Sqrt(sq / float64(len(offsets)))
+	t.UnstableRate = t.StdDev * 10
+
+	return t
+}
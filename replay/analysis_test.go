@@ -0,0 +1,140 @@
+package replay
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/qua"
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestAnalyze(t *testing.T) {
+	// Played at 1.5x, so map offsets of -30, -15, 15 and 30 are -20, -10, 10
+	// and 20 in real time.
+	r := &Result{
+		Rate: 1.5,
+		Hits: []Hit{
+			{Object: 0, Lane: 1, Time: 1000, Offset: -30, Input: true, Judgement: JudgementPerfect},
+			{Object: 1, Lane: 2, Time: 2000, Offset: -15, Input: true, Judgement: JudgementMarvelous},
+			{Object: 2, Lane: 1, Time: 3000, Offset: 15, Input: true, Judgement: JudgementMarvelous},
+			{Object: 2, Lane: 1, Release: true, Time: 3500, Offset: 45, Input: true, Judgement: JudgementGreat},
+			{Object: 3, Lane: 2, Time: 4000, Offset: 30, Input: true, Judgement: JudgementPerfect},
+			{Object: 4, Lane: 3, Time: 5000, Judgement: JudgementMiss},
+		},
+	}
+	a := r.Analyze()
+
+	// The mean is 0 and the deviations are 20, 10, 10 and 20, so the standard
+	// deviation is sqrt(1000 / 4).
+	stdDev := math.Sqrt(250)
+	want := Timing{Count: 4, Early: 2, Late: 2, StdDev: stdDev, UnstableRate: 10 * stdDev, Min: -20, Max: 20}
+	if !timingEqual(a.Press, want) {
+		t.Errorf("Press = %+v, want %+v", a.Press, want)
+	}
+	want = Timing{Count: 1, Late: 1, Mean: 30, Min: 30, Max: 30}
+	if !timingEqual(a.Release, want) {
+		t.Errorf("Release = %+v, want %+v", a.Release, want)
+	}
+
+	// The miss has no input, so it is left out of the timing.
+	if len(a.Inputs) != 5 || a.Inputs[3].Offset != 30 || !a.Inputs[3].Release {
+		t.Errorf("Inputs = %+v, want 5 with the release fourth", a.Inputs)
+	}
+
+	lanes := []struct {
+		lane   int
+		counts [6]int
+		mean   float64
+	}{
+		{1, [6]int{1, 1, 1, 0, 0, 0}, -5},
+		{2, [6]int{1, 1, 0, 0, 0, 0}, 5},
+		{3, [6]int{0, 0, 0, 0, 0, 1}, 0},
+	}
+	if len(a.Lanes) != len(lanes) {
+		t.Fatalf("Lanes = %+v, want %d lanes", a.Lanes, len(lanes))
+	}
+	for i, want := range lanes {
+		l := a.Lanes[i]
+		counts := [6]int{l.CountMarvelous, l.CountPerfect, l.CountGreat, l.CountGood, l.CountOkay, l.CountMiss}
+		if l.Lane != want.lane || counts != want.counts || l.Timing.Mean != want.mean {
+			t.Errorf("Lanes[%d] = lane %d with %v and mean %v, want lane %d with %v and mean %v",
+				i, l.Lane, counts, l.Timing.Mean, want.lane, want.counts, want.mean)
+		}
+	}
+	if a.Lanes[2].Accuracy != 0 || a.Lanes[1].Accuracy <= a.Lanes[0].Accuracy {
+		t.Errorf("lane accuracies = %v, %v, %v", a.Lanes[0].Accuracy, a.Lanes[1].Accuracy, a.Lanes[2].Accuracy)
+	}
+}
+
+func TestAnalyzeHistogram(t *testing.T) {
+	a := (&Result{Rate: 1, Hits: []Hit{
+		{Offset: -20, Input: true},
+		{Offset: -10, Input: true},
+		{Offset: -1, Input: true},
+		{Offset: 10, Input: true},
+		{Offset: 19, Input: true},
+		{Offset: 100, Release: true, Input: true},
+	}}).Analyze()
+
+	// Buckets include their start and exclude their end, and the empty bucket
+	// from 0 to 10 between filled ones is kept.
+	want := []Bucket{
+		{Start: -20, End: -10, Count: 1},
+		{Start: -10, End: 0, Count: 2},
+		{Start: 0, End: 10, Count: 0},
+		{Start: 10, End: 20, Count: 2},
+	}
+	if got := a.Histogram(10, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("Histogram(10, false) = %+v, want %+v", got, want)
+	}
+
+	want = []Bucket{{Start: 100, End: 125, Count: 1}}
+	if got := a.Histogram(25, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("Histogram(25, true) = %+v, want %+v", got, want)
+	}
+
+	if got := a.Histogram(0, false); got != nil {
+		t.Errorf("Histogram(0, false) = %+v, want nil", got)
+	}
+	if got := (&Analysis{}).Histogram(10, false); got != nil {
+		t.Errorf("Histogram of no inputs = %+v, want nil", got)
+	}
+}
+
+func TestAnalyzeSimulated(t *testing.T) {
+	m := &qua.Map{
+		Mode: quaver.GameMode4K,
+		HitObjects: []qua.HitObject{
+			{StartTime: 1000, Lane: 1},
+			{StartTime: 2000, Lane: 2},
+			{StartTime: 3000, Lane: 3},
+			{StartTime: 4000, Lane: 4},
+		},
+	}
+	var frames []Frame
+	for i, offset := range []int{-12, -4, 4, 12} {
+		at := 1000*(i+1) + offset
+		frames = append(frames, Frame{Time: at, Keys: 1 << i}, Frame{Time: at + 30})
+	}
+
+	res, err := Simulate(m, &Replay{Mode: quaver.GameMode4K, Frames: frames})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := res.Analyze()
+
+	// Deviations of 12, 4, 4 and 12 give a variance of 80.
+	stdDev := math.Sqrt(80)
+	want := Timing{Count: 4, Early: 2, Late: 2, StdDev: stdDev, UnstableRate: 10 * stdDev, Min: -12, Max: 12}
+	if !timingEqual(a.Press, want) {
+		t.Errorf("Press = %+v, want %+v", a.Press, want)
+	}
+}
+
+func timingEqual(a, b Timing) bool {
+	const eps = 1e-9
+	return a.Count == b.Count && a.Early == b.Early && a.Late == b.Late &&
+		math.Abs(a.Mean-b.Mean) < eps && math.Abs(a.StdDev-b.StdDev) < eps &&
+		math.Abs(a.UnstableRate-b.UnstableRate) < eps && a.Min == b.Min && a.Max == b.Max
+}
@@ -0,0 +1,178 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ulikunitz/xz/lzma"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// maxStringLength bounds the strings read from a replay header, so a corrupt
+// length prefix cannot allocate unbounded memory.
+const maxStringLength = 1 << 16
+
+// Parse parses the contents of a .qr file.
+func Parse(b []byte) (*Replay, error) {
+	return Decode(bytes.NewReader(b))
+}
+
+// ParseFile reads and parses the .qr file at path.
+func ParseFile(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Decode(f)
+}
+
+// Decode reads and parses a .qr file from r.
+func Decode(r io.Reader) (*Replay, error) {
+	d := &decoder{r: bufio.NewReader(r)}
+
+	rp := &Replay{}
+	rp.QuaverVersion = d.string("version")
+	rp.MapMD5 = d.string("map MD5")
+	rp.MD5 = d.string("replay MD5")
+	rp.PlayerName = d.string("player name")
+	rp.Date = d.string("date")
+	rp.TimePlayed = d.int64("time played")
+	rp.Mode = quaver.GameMode(d.int32("mode"))
+	if rp.QuaverVersion == versionNone {
+		rp.Modifiers = quaver.Modifier(d.int32("modifiers"))
+	} else {
+		rp.Modifiers = quaver.Modifier(d.int64("modifiers"))
+	}
+	rp.Score = d.int32("score")
+	rp.Accuracy = d.float32("accuracy")
+	rp.MaxCombo = d.int32("max combo")
+	rp.CountMarvelous = d.int32("marvelous count")
+	rp.CountPerfect = d.int32("perfect count")
+	rp.CountGreat = d.int32("great count")
+	rp.CountGood = d.int32("good count")
+	rp.CountOkay = d.int32("okay count")
+	rp.CountMiss = d.int32("miss count")
+	rp.PauseCount = d.int32("pause count")
+	if rp.QuaverVersion != versionNone {
+		rp.RandomizeModifierSeed = d.int32("randomize seed")
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if rp.Mode != quaver.GameMode4K && rp.Mode != quaver.GameMode7K {
+		return nil, fmt.Errorf("replay: unknown game mode %d", rp.Mode)
+	}
+
+	frames, err := lzma.NewReader(d.r)
+	if err != nil {
+		return nil, fmt.Errorf("replay: reading frames: %w", err)
+	}
+	data, err := io.ReadAll(frames)
+	if err != nil {
+		return nil, fmt.Errorf("replay: reading frames: %w", err)
+	}
+
+	rp.Frames, err = parseFrames(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return rp, nil
+}
+
+// parseFrames parses frames written as "time|keys," pairs.
+func parseFrames(s string) ([]Frame, error) {
+	var frames []Frame
+	for i, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		t, k, ok := strings.Cut(f, "|")
+		if !ok {
+			return nil, fmt.Errorf("replay: frame %d: malformed frame %q", i, f)
+		}
+		time, err := strconv.Atoi(t)
+		if err != nil {
+			return nil, fmt.Errorf("replay: frame %d: invalid time %q", i, t)
+		}
+		keys, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, fmt.Errorf("replay: frame %d: invalid keys %q", i, k)
+		}
+
+		frames = append(frames, Frame{Time: time, Keys: Keys(keys)})
+	}
+	return frames, nil
+}
+
+// decoder reads the little-endian header fields written by .NET's
+// BinaryWriter, keeping the first error.
+type decoder struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (d *decoder) fail(field string, err error) {
+	if d.err != nil {
+		return
+	}
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	d.err = fmt.Errorf("replay: reading %v: %w", field, err)
+}
+
+func (d *decoder) read(field string, n int) []byte {
+	if d.err != nil {
+		return make([]byte, n)
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(d.r, b)
+	if err != nil {
+		d.fail(field, err)
+	}
+	return b
+}
+
+// string reads a string prefixed with its length as a 7-bit encoded integer.
+func (d *decoder) string(field string) string {
+	if d.err != nil {
+		return ""
+	}
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.fail(field, err)
+		return ""
+	}
+	if n > maxStringLength {
+		d.fail(field, fmt.Errorf("string of length %d too long", n))
+		return ""
+	}
+	return string(d.read(field, int(n)))
+}
+
+func (d *decoder) int32(field string) int {
+	return int(int32(binary.LittleEndian.Uint32(d.read(field, 4))))
+}
+
+func (d *decoder) int64(field string) int64 {
+	return int64(binary.LittleEndian.Uint64(d.read(field, 8)))
+}
+
+func (d *decoder) float32(field string) float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(d.read(field, 4)))
+}
@@ -0,0 +1,165 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// want holds the fields of a replay checked by checkReplay.
+type want struct {
+	Version    string   `json:"version"`
+	MapMD5     string   `json:"map_md5"`
+	Player     string   `json:"player"`
+	Mode       int      `json:"mode"`
+	Modifiers  string   `json:"modifiers"`
+	TimePlayed int64    `json:"time_played"`
+	Score      int      `json:"score"`
+	MaxCombo   int      `json:"max_combo"`
+	Judgements [6]int   `json:"judgements"`
+	Frames     int      `json:"frames"`
+	First      [][2]int `json:"first_frames"`
+}
+
+// checkReplay compares the decoded fields of r with w one at a time.
+func checkReplay(t *testing.T, r *Replay, w want) {
+	t.Helper()
+
+	mods, err := quaver.ParseModifier(w.Modifiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	judgements := [6]int{r.CountMarvelous, r.CountPerfect, r.CountGreat, r.CountGood, r.CountOkay, r.CountMiss}
+
+	fields := []struct {
+		name      string
+		got, want any
+	}{
+		{"QuaverVersion", r.QuaverVersion, w.Version},
+		{"MapMD5", r.MapMD5, w.MapMD5},
+		{"PlayerName", r.PlayerName, w.Player},
+		{"Mode", r.Mode, quaver.GameMode(w.Mode)},
+		{"Modifiers", r.Modifiers, mods},
+		{"TimePlayed", r.TimePlayed, w.TimePlayed},
+		{"Score", r.Score, w.Score},
+		{"MaxCombo", r.MaxCombo, w.MaxCombo},
+		{"judgements", judgements, w.Judgements},
+		{"len(Frames)", len(r.Frames), w.Frames},
+	}
+	for _, f := range fields {
+		if f.got != f.want {
+			t.Errorf("%v = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+
+	for i, f := range w.First {
+		if i >= len(r.Frames) {
+			break
+		}
+		if got := r.Frames[i]; got.Time != f[0] || got.Keys != Keys(f[1]) {
+			t.Errorf("Frames[%d] = %+v, want time %d keys %d", i, got, f[0], f[1])
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		path string
+		want want
+	}{
+		{"testdata/4k.qr", want{
+			Version:    "0.9.1",
+			MapMD5:     "5d41402abc4b2a76b9719d911017c592",
+			Player:     "player",
+			Mode:       1,
+			Modifiers:  "1.2x, MR",
+			TimePlayed: 1792268102000,
+			Score:      912345,
+			MaxCombo:   812,
+			Judgements: [6]int{700, 90, 15, 4, 2, 3},
+			Frames:     400,
+			First:      [][2]int{{-500, 0}, {-483, 1}, {-465, 2}, {-446, 3}},
+		}},
+		// Replays from before versioning store 32-bit modifiers and no seed.
+		{"testdata/7k_none.qr", want{
+			Version:    "None",
+			MapMD5:     "7d793037a0760186574b0282f2f435e7",
+			Player:     "old player",
+			Mode:       2,
+			Modifiers:  "NLN",
+			TimePlayed: 1546398245000,
+			Score:      1000000,
+			MaxCombo:   3,
+			Judgements: [6]int{3, 0, 0, 0, 0, 0},
+			Frames:     7,
+			First:      [][2]int{{0, 0}, {1000, 1}, {1010, 0}, {1500, 64}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+			r, err := ParseFile(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkReplay(t, r, tt.want)
+		})
+	}
+
+	r, err := ParseFile("testdata/4k.qr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Accuracy != 97.8125 || r.PauseCount != 1 || r.RandomizeModifierSeed != -1 {
+		t.Errorf("Accuracy, PauseCount, RandomizeModifierSeed = %v, %v, %v, want 97.8125, 1, -1",
+			r.Accuracy, r.PauseCount, r.RandomizeModifierSeed)
+	}
+	if got := r.Played().Format("2006-01-02 15:04:05"); got != "2026-10-17 20:15:02" || r.Date != "10/17/2026 8:15:02 PM" {
+		t.Errorf("Played = %v and Date = %q, want the same time", got, r.Date)
+	}
+}
+
+// TestDecodeGameReplays decodes replays saved by the game, kept unmodified in
+// testdata/game. Each replay has a .json file beside it with the fields the
+// game showed for the play, in the format of want.
+func TestDecodeGameReplays(t *testing.T) {
+	paths, err := filepath.Glob("testdata/game/*.qr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Skip("no replays saved by the game in testdata/game")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			b, err := os.ReadFile(strings.TrimSuffix(path, ".qr") + ".json")
+			if errors.Is(err, fs.ErrNotExist) {
+				t.Fatalf("%v has no .json file with its expected fields", path)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var w want
+			if err := json.Unmarshal(b, &w); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := ParseFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkReplay(t, r, w)
+
+			if !slices.IsSortedFunc(r.Frames, func(a, b Frame) int { return a.Time - b.Time }) {
+				t.Error("frames are not in time order")
+			}
+		})
+	}
+}
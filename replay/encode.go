@@ -0,0 +1,141 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/ulikunitz/xz/lzma"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// Marshal returns the .qr encoding of r.
+//
+// Decoding the result yields a replay equal to r, and marshaling a replay
+// decoded from a file reproduces its header and frames, though the frames
+// may be compressed differently than the game compressed them. r.MD5 is
+// written as is; set it with ContentMD5 after editing a replay.
+func Marshal(r *Replay) ([]byte, error) {
+	var buf bytes.Buffer
+	err := Encode(&buf, r)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode writes the .qr encoding of r to w. See Marshal for details.
+func Encode(w io.Writer, r *Replay) error {
+	if r.Mode != quaver.GameMode4K && r.Mode != quaver.GameMode7K {
+		return fmt.Errorf("replay: unknown game mode %d", r.Mode)
+	}
+	if r.QuaverVersion == versionNone && int64(int32(r.Modifiers)) != int64(r.Modifiers) {
+		return fmt.Errorf("replay: modifiers %d do not fit a replay of version %q", r.Modifiers, versionNone)
+	}
+
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.header(r, r.MD5)
+
+	frames := formatFrames(r.Frames)
+	cfg := lzma.WriterConfig{SizeInHeader: true, Size: int64(len(frames))}
+	lw, err := cfg.NewWriter(e.w)
+	if err != nil {
+		return fmt.Errorf("replay: writing frames: %w", err)
+	}
+	_, err = lw.Write(frames)
+	if err != nil {
+		return fmt.Errorf("replay: writing frames: %w", err)
+	}
+	err = lw.Close()
+	if err != nil {
+		return fmt.Errorf("replay: writing frames: %w", err)
+	}
+
+	return e.w.Flush()
+}
+
+// ContentMD5 returns the hex encoded MD5 hash of the header and frames of r,
+// without r.MD5 itself. Assign it to r.MD5 after editing a replay so the hash
+// changes with its contents.
+//
+// The hash is computed by this package. It is not the hash the game gives
+// replays, so it cannot be used to look up a replay on the Quaver API.
+func (r *Replay) ContentMD5() string {
+	h := md5.New()
+	e := &encoder{w: bufio.NewWriter(h)}
+	e.header(r, "")
+	e.w.Write(formatFrames(r.Frames))
+	e.w.Flush()
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// header writes the header of r with the given replay hash.
+func (e *encoder) header(r *Replay, hash string) {
+	e.string(r.QuaverVersion)
+	e.string(r.MapMD5)
+	e.string(hash)
+	e.string(r.PlayerName)
+	e.string(r.Date)
+	e.int64(r.TimePlayed)
+	e.int32(int(r.Mode))
+	if r.QuaverVersion == versionNone {
+		e.int32(int(r.Modifiers))
+	} else {
+		e.int64(int64(r.Modifiers))
+	}
+	e.int32(r.Score)
+	e.float32(r.Accuracy)
+	e.int32(r.MaxCombo)
+	e.int32(r.CountMarvelous)
+	e.int32(r.CountPerfect)
+	e.int32(r.CountGreat)
+	e.int32(r.CountGood)
+	e.int32(r.CountOkay)
+	e.int32(r.CountMiss)
+	e.int32(r.PauseCount)
+	if r.QuaverVersion != versionNone {
+		e.int32(r.RandomizeModifierSeed)
+	}
+}
+
+// formatFrames writes frames as "time|keys," pairs.
+func formatFrames(frames []Frame) []byte {
+	var b []byte
+	for _, f := range frames {
+		b = strconv.AppendInt(b, int64(f.Time), 10)
+		b = append(b, '|')
+		b = strconv.AppendInt(b, int64(f.Keys), 10)
+		b = append(b, ',')
+	}
+	return b
+}
+
+// encoder writes header fields in the layout read by decoder. Write errors
+// are sticky in the bufio.Writer and reported by Flush.
+type encoder struct {
+	w *bufio.Writer
+}
+
+func (e *encoder) string(s string) {
+	e.w.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	e.w.WriteString(s)
+}
+
+func (e *encoder) int32(v int) {
+	e.w.Write(binary.LittleEndian.AppendUint32(nil, uint32(int32(v))))
+}
+
+func (e *encoder) int64(v int64) {
+	e.w.Write(binary.LittleEndian.AppendUint64(nil, uint64(v)))
+}
+
+func (e *encoder) float32(v float32) {
+	e.w.Write(binary.LittleEndian.AppendUint32(nil, math.Float32bits(v)))
+}
@@ -0,0 +1,85 @@
+package replay
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	paths, err := filepath.Glob("testdata/*.qr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no fixtures in testdata")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := Parse(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := Marshal(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again, err := Parse(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(again, r) {
+				t.Errorf("Parse(Marshal(r)) = %+v, want %+v", again, r)
+			}
+
+			// The fixtures were written by Marshal, so compress the same way.
+			if !bytes.Equal(out, b) {
+				t.Error("Marshal does not reproduce the file")
+			}
+		})
+	}
+}
+
+func TestContentMD5(t *testing.T) {
+	r, err := ParseFile("testdata/4k.qr")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := r.ContentMD5()
+	if sum != r.MD5 {
+		t.Errorf("ContentMD5 = %v, want the fixture's %v", sum, r.MD5)
+	}
+
+	r.MD5 = "stale"
+	if got := r.ContentMD5(); got != sum {
+		t.Errorf("ContentMD5 depends on MD5: got %v, want %v", got, sum)
+	}
+
+	r.Frames[10].Keys ^= 1
+	edited := r.ContentMD5()
+	if edited == sum {
+		t.Error("ContentMD5 did not change with the frames")
+	}
+
+	r.MD5 = edited
+	b, err := Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.MD5 != edited || again.ContentMD5() != edited {
+		t.Errorf("MD5 = %v and ContentMD5 = %v after round trip, want %v", again.MD5, again.ContentMD5(), edited)
+	}
+}
@@ -0,0 +1,59 @@
+package replay
+
+import "github.com/maskeddd/go-quaver/quaver"
+
+// Judgement is the judgement given to a press or release.
+type Judgement int
+
+const (
+	JudgementMarvelous Judgement = iota
+	JudgementPerfect
+	JudgementGreat
+	JudgementGood
+	JudgementOkay
+	JudgementMiss
+)
+
+func (j Judgement) String() string {
+	return [...]string{"Marvelous", "Perfect", "Great", "Good", "Okay", "Miss"}[j]
+}
+
+// judgementWindows are the largest offsets in milliseconds, at 1.0x and
+// without Strict or Chill, that receive each judgement. Late presses past the
+// Okay window are misses; only early presses use the Miss window.
+var judgementWindows = [...]float64{18, 43, 76, 106, 127, 164}
+
+const (
+	strictWindowMultiplier  = 0.9
+	chillWindowMultiplier   = 1.2
+	releaseWindowMultiplier = 1.5
+)
+
+// windows returns the press and release windows for mods in map time.
+func windows(mods quaver.Modifier) (press, release [len(judgementWindows)]float64) {
+	m := mods.Rate()
+	switch {
+	case mods&quaver.ModifierStrict != 0:
+		m *= strictWindowMultiplier
+	case mods&quaver.ModifierChill != 0:
+		m *= chillWindowMultiplier
+	}
+
+	for j, w := range judgementWindows {
+		press[j] = w * m
+		release[j] = w * m * releaseWindowMultiplier
+	}
+	return press, release
+}
+
+// judgements converts counts indexed by Judgement.
+func judgements(counts [len(judgementWindows)]int) quaver.Judgements {
+	return quaver.Judgements{
+		Marvelous: counts[JudgementMarvelous],
+		Perfect:   counts[JudgementPerfect],
+		Great:     counts[JudgementGreat],
+		Good:      counts[JudgementGood],
+		Okay:      counts[JudgementOkay],
+		Miss:      counts[JudgementMiss],
+	}
+}
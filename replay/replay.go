@@ -0,0 +1,70 @@
+// Package replay reads Quaver .qr replay files.
+package replay
+
+import (
+	"time"
+
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// versionNone is the version written by replays from before replays were
+// versioned. They store 32-bit modifiers and no randomize seed.
+const versionNone = "None"
+
+// Replay is a parsed .qr file.
+type Replay struct {
+	// QuaverVersion is the replay format version, or "None" for old replays.
+	QuaverVersion string
+
+	MapMD5     string
+	MD5        string
+	PlayerName string
+
+	// Date is the date the replay was played, as written by the game. Use
+	// TimePlayed for a machine readable time.
+	Date string
+
+	// TimePlayed is the time the replay was played, in milliseconds since the
+	// Unix epoch.
+	TimePlayed int64
+
+	Mode      quaver.GameMode
+	Modifiers quaver.Modifier
+
+	Score          int
+	Accuracy       float32
+	MaxCombo       int
+	CountMarvelous int
+	CountPerfect   int
+	CountGreat     int
+	CountGood      int
+	CountOkay      int
+	CountMiss      int
+	PauseCount     int
+
+	// RandomizeModifierSeed is the seed used to shuffle lanes when the
+	// Randomize modifier is active.
+	RandomizeModifierSeed int
+
+	Frames []Frame
+}
+
+// Frame is the state of the keys from a point in time until the next frame.
+type Frame struct {
+	// Time is the time of the frame in milliseconds, in map time.
+	Time int
+	Keys Keys
+}
+
+// Keys is the set of lanes held down. Lane 1 is the lowest bit.
+type Keys int
+
+// IsPressed reports whether the 1-based lane is held down.
+func (k Keys) IsPressed(lane int) bool {
+	return k&(1<<(lane-1)) != 0
+}
+
+// Played returns the time the replay was played.
+func (r *Replay) Played() time.Time {
+	return time.UnixMilli(r.TimePlayed).UTC()
+}
@@ -0,0 +1,342 @@
+package replay
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/maskeddd/go-quaver/qua"
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+// ErrUnsupportedModifiers is returned by Simulate for replays played with
+// modifiers that rearrange the map in ways it does not reproduce.
+var ErrUnsupportedModifiers = errors.New("replay: unsupported modifiers")
+
+// unsupportedModifiers are the modifiers Simulate cannot apply to a map.
+const unsupportedModifiers = quaver.ModifierRandomize | quaver.ModifierInverse | quaver.ModifierFullLN
+
+// Result is the outcome of playing a replay on a map.
+type Result struct {
+	CountMarvelous int
+	CountPerfect   int
+	CountGreat     int
+	CountGood      int
+	CountOkay      int
+	CountMiss      int
+	MaxCombo       int
+	Accuracy       float64
+	Grade          quaver.Grade
+
+	// Rate is the audio rate the replay was played at.
+	Rate float64
+
+	// Hits holds every judgement in order of the time it was due.
+	Hits []Hit
+}
+
+// Hit is the judgement of one press or release.
+type Hit struct {
+	// Object is the index of the hit object in the map's HitObjects.
+	Object int
+	// Lane is the lane the object was played in, after Mirror.
+	Lane int
+	// Release reports whether this judges the end of a long note.
+	Release bool
+	// Time is the time the press or release was due.
+	Time int
+	// Offset is the time of the input minus Time in map milliseconds, so
+	// negative offsets are early. Divide by the rate for real time.
+	Offset int
+	// Input reports whether the judgement came from a key press or release.
+	// Notes that were never pressed and long notes held past their release
+	// window have no input, and their Offset is meaningless.
+	Input     bool
+	Judgement Judgement
+}
+
+// Judgements returns the judgement counts of the result.
+func (r *Result) Judgements() quaver.Judgements {
+	return quaver.Judgements{
+		Marvelous: r.CountMarvelous,
+		Perfect:   r.CountPerfect,
+		Great:     r.CountGreat,
+		Good:      r.CountGood,
+		Okay:      r.CountOkay,
+		Miss:      r.CountMiss,
+	}
+}
+
+// Discrepancy is a value that differs between a Result and a submitted score.
+type Discrepancy struct {
+	Field     string
+	Simulated float64
+	Submitted float64
+}
+
+func (d Discrepancy) String() string {
+	return fmt.Sprintf("%v: simulated %v, submitted %v", d.Field, d.Simulated, d.Submitted)
+}
+
+// accuracyTolerance is the largest accuracy difference Compare ignores, to
+// allow for the precision scores are stored with.
+const accuracyTolerance = 0.01
+
+// Compare returns the judgement counts, combo and accuracy that differ
+// between r and s.
+func (r *Result) Compare(s *quaver.Score) []Discrepancy {
+	var d []Discrepancy
+	check := func(field string, simulated, submitted float64, tolerance float64) {
+		if math.Abs(simulated-submitted) > tolerance {
+			d = append(d, Discrepancy{Field: field, Simulated: simulated, Submitted: submitted})
+		}
+	}
+
+	check("CountMarvelous", float64(r.CountMarvelous), float64(s.CountMarvelous), 0)
+	check("CountPerfect", float64(r.CountPerfect), float64(s.CountPerfect), 0)
+	check("CountGreat", float64(r.CountGreat), float64(s.CountGreat), 0)
+	check("CountGood", float64(r.CountGood), float64(s.CountGood), 0)
+	check("CountOkay", float64(r.CountOkay), float64(s.CountOkay), 0)
+	check("CountMiss", float64(r.CountMiss), float64(s.CountMiss), 0)
+	check("MaxCombo", float64(r.MaxCombo), float64(s.MaxCombo), 0)
+	check("Accuracy", r.Accuracy, s.Accuracy, accuracyTolerance)
+	return d
+}
+
+// Simulate plays r on m and judges every note the way the game does,
+// including Strict and Chill windows, rate scaling, Mirror and No Long Notes.
+// Replays using Randomize, Inverse or Full LN return ErrUnsupportedModifiers.
+//
+// Missed long notes count as two misses, one for the press and one for the
+// release. Releasing a long note early, or holding it past its release
+// window, is judged Okay. Health and failing are not simulated.
+//
+// Simulate does not check that r was played on m.
+func Simulate(m *qua.Map, r *Replay) (*Result, error) {
+	if r.Modifiers&unsupportedModifiers != 0 {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedModifiers, r.Modifiers&unsupportedModifiers)
+	}
+
+	s := newSimulator(m, r.Modifiers)
+
+	var prev Keys
+	for _, f := range r.Frames {
+		s.expire(f.Time)
+
+		changed := prev ^ f.Keys
+		for lane := 1; lane <= len(s.lanes); lane++ {
+			if !changed.IsPressed(lane) {
+				continue
+			}
+			if f.Keys.IsPressed(lane) {
+				s.press(lane, f.Time)
+			} else {
+				s.release(lane, f.Time)
+			}
+		}
+		prev = f.Keys
+	}
+	s.expire(math.MaxInt32)
+
+	return s.result(), nil
+}
+
+// RatingsAccuracy returns the accuracy used for the performance rating of r
+// on m. Plays with Strict or Chill are judged again with the standard
+// windows, as quaver.Judgements.RatingsAccuracy cannot do from counts alone.
+func RatingsAccuracy(m *qua.Map, r *Replay) (float64, error) {
+	standard := *r
+	standard.Modifiers &^= quaver.ModifierStrict | quaver.ModifierChill
+
+	res, err := Simulate(m, &standard)
+	if err != nil {
+		return 0, err
+	}
+	return res.Accuracy, nil
+}
+
+type note struct {
+	index      int
+	lane       int
+	start, end int
+	long       bool
+}
+
+type simulator struct {
+	rate                         float64
+	pressWindows, releaseWindows [len(judgementWindows)]float64
+
+	// lanes holds the notes not yet pressed in each lane, in order.
+	lanes [][]*note
+	// held holds the long note being held in each lane.
+	held []*note
+
+	counts   [len(judgementWindows)]int
+	combo    int
+	maxCombo int
+	hits     []Hit
+}
+
+func newSimulator(m *qua.Map, mods quaver.Modifier) *simulator {
+	keys := m.KeyCount()
+	s := &simulator{
+		rate:  mods.Rate(),
+		lanes: make([][]*note, keys),
+		held:  make([]*note, keys),
+	}
+	s.pressWindows, s.releaseWindows = windows(mods)
+
+	// Mirror flips the playfield lanes and leaves the scratch lane in place.
+	mirrored := keys
+	if m.HasScratchKey {
+		mirrored--
+	}
+
+	for i, h := range m.HitObjects {
+		n := &note{index: i, lane: h.Lane, start: h.StartTime, end: h.EndTime, long: h.IsLongNote()}
+		if mods&quaver.ModifierMirror != 0 && n.lane <= mirrored {
+			n.lane = mirrored + 1 - n.lane
+		}
+		if mods&quaver.ModifierNoLongNotes != 0 {
+			n.end, n.long = 0, false
+		}
+		if n.lane < 1 || n.lane > keys {
+			continue
+		}
+		s.lanes[n.lane-1] = append(s.lanes[n.lane-1], n)
+	}
+	for _, lane := range s.lanes {
+		slices.SortStableFunc(lane, func(a, b *note) int {
+			return cmp.Compare(a.start, b.start)
+		})
+	}
+
+	return s
+}
+
+// expire misses the notes and long note releases whose windows closed before
+// t.
+func (s *simulator) expire(t int) {
+	okay := s.pressWindows[JudgementOkay]
+	for i, lane := range s.lanes {
+		for len(lane) > 0 && float64(t-lane[0].start) > okay {
+			n := lane[0]
+			lane = lane[1:]
+			s.judge(n, false, 0, false, JudgementMiss)
+			if n.long {
+				s.judge(n, true, 0, false, JudgementMiss)
+			}
+		}
+		s.lanes[i] = lane
+	}
+
+	okay = s.releaseWindows[JudgementOkay]
+	for i, n := range s.held {
+		if n != nil && float64(t-n.end) > okay {
+			s.held[i] = nil
+			s.judge(n, true, 0, false, JudgementOkay)
+		}
+	}
+}
+
+// press judges a key press against the next note in the lane. Presses
+// outside every window are ignored.
+func (s *simulator) press(lane, t int) {
+	notes := s.lanes[lane-1]
+	if len(notes) == 0 || s.held[lane-1] != nil {
+		return
+	}
+
+	n := notes[0]
+	offset := t - n.start
+	j, ok := lookup(s.pressWindows[:], offset)
+	if !ok {
+		return
+	}
+	s.lanes[lane-1] = notes[1:]
+
+	s.judge(n, false, offset, true, j)
+	if !n.long {
+		return
+	}
+	if j == JudgementMiss {
+		s.judge(n, true, 0, false, JudgementMiss)
+		return
+	}
+	s.held[lane-1] = n
+}
+
+// release judges the end of the long note held in the lane.
+func (s *simulator) release(lane, t int) {
+	n := s.held[lane-1]
+	if n == nil {
+		return
+	}
+	s.held[lane-1] = nil
+
+	offset := t - n.end
+	j, ok := lookup(s.releaseWindows[:JudgementMiss], offset)
+	if !ok {
+		j = JudgementOkay
+	}
+	s.judge(n, true, offset, true, j)
+}
+
+// lookup returns the judgement of the first window containing offset.
+func lookup(windows []float64, offset int) (Judgement, bool) {
+	d := math.Abs(float64(offset))
+	for j, w := range windows {
+		if d <= w {
+			return Judgement(j), true
+		}
+	}
+	return 0, false
+}
+
+func (s *simulator) judge(n *note, release bool, offset int, input bool, j Judgement) {
+	t := n.start
+	if release {
+		t = n.end
+	}
+	s.hits = append(s.hits, Hit{
+		Object:    n.index,
+		Lane:      n.lane,
+		Release:   release,
+		Time:      t,
+		Offset:    offset,
+		Input:     input,
+		Judgement: j,
+	})
+
+	s.counts[j]++
+	if j == JudgementMiss {
+		s.combo = 0
+	} else {
+		s.combo++
+		s.maxCombo = max(s.maxCombo, s.combo)
+	}
+}
+
+func (s *simulator) result() *Result {
+	slices.SortStableFunc(s.hits, func(a, b Hit) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+
+	j := judgements(s.counts)
+	acc := j.Accuracy()
+	return &Result{
+		CountMarvelous: j.Marvelous,
+		CountPerfect:   j.Perfect,
+		CountGreat:     j.Great,
+		CountGood:      j.Good,
+		CountOkay:      j.Okay,
+		CountMiss:      j.Miss,
+		MaxCombo:       s.maxCombo,
+		Accuracy:       acc,
+		Grade:          quaver.AccuracyGrade(acc),
+		Rate:           s.rate,
+		Hits:           s.hits,
+	}
+}
@@ -0,0 +1,263 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/maskeddd/go-quaver/qua"
+	"github.com/maskeddd/go-quaver/quaver"
+)
+
+func TestSimulateMirrorKeepsScratchLane(t *testing.T) {
+	m := &qua.Map{
+		Mode:          quaver.GameMode7K,
+		HasScratchKey: true,
+		HitObjects: []qua.HitObject{
+			{StartTime: 1000, Lane: 1},
+			{StartTime: 2000, Lane: 8},
+			{StartTime: 3000, Lane: 3},
+		},
+	}
+	r := &Replay{
+		Mode:      quaver.GameMode7K,
+		Modifiers: quaver.ModifierMirror,
+		Frames: []Frame{
+			{Time: 0, Keys: 0},
+			{Time: 1000, Keys: 1 << 6},
+			{Time: 1050, Keys: 0},
+			{Time: 2000, Keys: 1 << 7},
+			{Time: 2050, Keys: 0},
+			{Time: 3000, Keys: 1 << 4},
+			{Time: 3050, Keys: 0},
+		},
+	}
+
+	res, err := Simulate(m, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.CountMarvelous != 3 {
+		t.Errorf("judgements = %+v, want 3 marvelous", res.Judgements())
+	}
+	for i, want := range []int{7, 8, 5} {
+		if got := res.Hits[i].Lane; got != want {
+			t.Errorf("hit %d lane = %d, want %d", i, got, want)
+		}
+	}
+}
+
+// noteTime is the time of the note the window tests play.
+const noteTime = 1000
+
+// pressAt returns the frames of a tap on lane 1 at offset from noteTime.
+func pressAt(offset int) []Frame {
+	return []Frame{
+		{Time: 0},
+		{Time: noteTime + offset, Keys: 1},
+		{Time: noteTime + offset + 5},
+	}
+}
+
+// simulateOne plays frames on a 4K map holding one note in lane 1 at noteTime,
+// ending at end if it is a long note, and returns the hits.
+func simulateOne(t *testing.T, mods quaver.Modifier, end int, frames []Frame) []Hit {
+	t.Helper()
+	m := &qua.Map{
+		Mode:       quaver.GameMode4K,
+		HitObjects: []qua.HitObject{{StartTime: noteTime, Lane: 1, EndTime: end}},
+	}
+	res, err := Simulate(m, &Replay{Mode: quaver.GameMode4K, Modifiers: mods, Frames: frames})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Hits
+}
+
+func TestSimulatePressWindows(t *testing.T) {
+	tests := []struct {
+		mods   quaver.Modifier
+		offset int
+		want   Judgement
+		// input is false for notes missed without a press that counts.
+		input bool
+	}{
+		// The windows at 1.0x are 18, 43, 76, 106 and 127, in both directions.
+		{0, 0, JudgementMarvelous, true},
+		{0, 18, JudgementMarvelous, true},
+		{0, -18, JudgementMarvelous, true},
+		{0, 19, JudgementPerfect, true},
+		{0, -19, JudgementPerfect, true},
+		{0, 43, JudgementPerfect, true},
+		{0, 44, JudgementGreat, true},
+		{0, 76, JudgementGreat, true},
+		{0, -77, JudgementGood, true},
+		{0, 106, JudgementGood, true},
+		{0, 107, JudgementOkay, true},
+		{0, -127, JudgementOkay, true},
+		{0, 127, JudgementOkay, true},
+		// Early presses up to 164 are misses; later ones are ignored, and a
+		// late press past Okay comes after the note was already missed.
+		{0, -128, JudgementMiss, true},
+		{0, -164, JudgementMiss, true},
+		{0, -165, JudgementMiss, false},
+		{0, 128, JudgementMiss, false},
+
+		// Rates scale the windows in map time.
+		{quaver.ModifierSpeed15X, 27, JudgementMarvelous, true},
+		{quaver.ModifierSpeed15X, 28, JudgementPerfect, true},
+		{quaver.ModifierSpeed15X, 190, JudgementOkay, true},
+		{quaver.ModifierSpeed15X, 191, JudgementMiss, false},
+		{quaver.ModifierSpeed05X, 9, JudgementMarvelous, true},
+		{quaver.ModifierSpeed05X, 10, JudgementPerfect, true},
+		{quaver.ModifierSpeed05X, -82, JudgementMiss, true},
+		{quaver.ModifierSpeed05X, -83, JudgementMiss, false},
+
+		// Strict narrows the windows to 0.9x and Chill widens them to 1.2x.
+		{quaver.ModifierStrict, 16, JudgementMarvelous, true},
+		{quaver.ModifierStrict, 17, JudgementPerfect, true},
+		{quaver.ModifierStrict, 114, JudgementOkay, true},
+		{quaver.ModifierStrict, 115, JudgementMiss, false},
+		{quaver.ModifierChill, 21, JudgementMarvelous, true},
+		{quaver.ModifierChill, 22, JudgementPerfect, true},
+		{quaver.ModifierChill, 152, JudgementOkay, true},
+		{quaver.ModifierChill, 153, JudgementMiss, false},
+		{quaver.ModifierChill | quaver.ModifierSpeed15X, 32, JudgementMarvelous, true},
+		{quaver.ModifierChill | quaver.ModifierSpeed15X, 33, JudgementPerfect, true},
+	}
+	for _, tt := range tests {
+		hits := simulateOne(t, tt.mods, 0, pressAt(tt.offset))
+		if len(hits) != 1 {
+			t.Fatalf("%v at %+d: %d hits, want 1", tt.mods, tt.offset, len(hits))
+		}
+		h := hits[0]
+		if h.Judgement != tt.want || h.Input != tt.input {
+			t.Errorf("%v at %+d = %v (input %v), want %v (input %v)", tt.mods, tt.offset, h.Judgement, h.Input, tt.want, tt.input)
+		}
+		if tt.input && h.Offset != tt.offset {
+			t.Errorf("%v at %+d: Offset = %d", tt.mods, tt.offset, h.Offset)
+		}
+	}
+}
+
+func TestSimulateReleaseWindows(t *testing.T) {
+	const end = 2000
+
+	tests := []struct {
+		mods   quaver.Modifier
+		offset int
+		want   Judgement
+		input  bool
+	}{
+		// Release windows are 1.5 times the press windows: 27, 64.5, 114, 159
+		// and 190.5.
+		{0, 0, JudgementMarvelous, true},
+		{0, 27, JudgementMarvelous, true},
+		{0, -28, JudgementPerfect, true},
+		{0, 64, JudgementPerfect, true},
+		{0, 65, JudgementGreat, true},
+		{0, -114, JudgementGreat, true},
+		{0, 115, JudgementGood, true},
+		{0, 159, JudgementGood, true},
+		{0, 160, JudgementOkay, true},
+		{0, -190, JudgementOkay, true},
+		// Releasing before every window is an Okay, and so is holding on
+		// until the release window closes.
+		{0, -191, JudgementOkay, true},
+		{0, -500, JudgementOkay, true},
+		{0, 191, JudgementOkay, false},
+
+		{quaver.ModifierSpeed15X, 40, JudgementMarvelous, true},
+		{quaver.ModifierSpeed15X, 41, JudgementPerfect, true},
+		{quaver.ModifierStrict, 24, JudgementMarvelous, true},
+		{quaver.ModifierStrict, 25, JudgementPerfect, true},
+		{quaver.ModifierChill, 32, JudgementMarvelous, true},
+		{quaver.ModifierChill, 33, JudgementPerfect, true},
+	}
+	for _, tt := range tests {
+		frames := []Frame{
+			{Time: 0},
+			{Time: noteTime, Keys: 1},
+			{Time: end + tt.offset},
+		}
+		hits := simulateOne(t, tt.mods, end, frames)
+		if len(hits) != 2 || hits[0].Judgement != JudgementMarvelous {
+			t.Fatalf("%v release at %+d: hits = %+v, want a marvelous press and a release", tt.mods, tt.offset, hits)
+		}
+		h := hits[1]
+		if !h.Release || h.Judgement != tt.want || h.Input != tt.input {
+			t.Errorf("%v release at %+d = %v (input %v), want %v (input %v)", tt.mods, tt.offset, h.Judgement, h.Input, tt.want, tt.input)
+		}
+	}
+}
+
+func TestSimulateLongNoteMiss(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames []Frame
+		want   []Hit
+	}{
+		{
+			"never pressed",
+			[]Frame{{Time: 0}},
+			[]Hit{
+				{Time: noteTime, Judgement: JudgementMiss},
+				{Time: 2000, Release: true, Judgement: JudgementMiss},
+			},
+		},
+		{
+			"pressed in the miss window",
+			[]Frame{{Time: 0}, {Time: noteTime - 150, Keys: 1}, {Time: 2000}},
+			[]Hit{
+				{Time: noteTime, Offset: -150, Input: true, Judgement: JudgementMiss},
+				{Time: 2000, Release: true, Judgement: JudgementMiss},
+			},
+		},
+		{
+			"released early",
+			[]Frame{{Time: 0}, {Time: noteTime, Keys: 1}, {Time: 1500}},
+			[]Hit{
+				{Time: noteTime, Input: true, Judgement: JudgementMarvelous},
+				{Time: 2000, Release: true, Offset: -500, Input: true, Judgement: JudgementOkay},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := simulateOne(t, 0, 2000, tt.frames)
+			if len(hits) != len(tt.want) {
+				t.Fatalf("hits = %+v, want %+v", hits, tt.want)
+			}
+			for i, want := range tt.want {
+				want.Lane = 1
+				if hits[i] != want {
+					t.Errorf("hit %d = %+v, want %+v", i, hits[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSimulateLongNoteCombo(t *testing.T) {
+	m := &qua.Map{
+		Mode: quaver.GameMode4K,
+		HitObjects: []qua.HitObject{
+			{StartTime: 1000, Lane: 1, EndTime: 2000},
+			{StartTime: 3000, Lane: 2},
+			{StartTime: 4000, Lane: 3},
+		},
+	}
+	// The long note is missed, which counts twice and breaks the combo.
+	r := &Replay{Mode: quaver.GameMode4K, Frames: []Frame{
+		{Time: 0},
+		{Time: 3000, Keys: 2},
+		{Time: 3010},
+		{Time: 4000, Keys: 4},
+		{Time: 4010},
+	}}
+	res, err := Simulate(m, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.CountMiss != 2 || res.CountMarvelous != 2 || res.MaxCombo != 2 {
+		t.Errorf("judgements = %+v with combo %d, want 2 misses, 2 marvelous and combo 2", res.Judgements(), res.MaxCombo)
+	}
+}